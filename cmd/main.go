@@ -5,7 +5,9 @@ import (
 	"os"
 	"task/config"
 	"task/handlers"
+	"task/internal"
 	"task/repository"
+	"task/worker"
 	"time"
 
 	_ "task/docs"
@@ -32,18 +34,56 @@ func init() {
 		config.Logger.Fatal("Ошибка загрузки .env файла")
 	}
 	config.Logger.Debug("Загрузка .env файла прошла успешно")
+}
+
+func setup() {
+	internal.LoadEnrichers()
+	config.Logger.Debug("Загрузка провайдеров обогащения прошла успешно")
 	repository.LoadDB()
 	config.Logger.Debug("Загрузка бд прошла успешно")
+	internal.LoadCache(repository.EnrichmentCacheStore{})
+	config.Logger.Debug("Загрузка кэша обогащения прошла успешно")
+	worker.Start()
+	config.Logger.Debug("Запуск воркеров обогащения прошёл успешно")
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(migrate(os.Args[2:]))
+	}
+	setup()
+
 	router := mux.NewRouter()
 
 	router.Use(loggingMidleware)
-	router.HandleFunc("/people", handlers.GetPeople).Methods("GET")
-	router.HandleFunc("/people", handlers.CreatePerson).Methods("POST")
-	router.HandleFunc("/people", handlers.UpdatePerson).Methods("PUT")
-	router.HandleFunc("/people", handlers.DeletePerson).Methods("DELETE")
+
+	v1 := router.PathPrefix("/api/v1").Subrouter()
+	v1.HandleFunc("/people", handlers.GetPeople).Methods("GET")
+	v1.HandleFunc("/people", handlers.CreatePerson).Methods("POST")
+	v1.HandleFunc("/people/bulk", handlers.CreatePeople).Methods("POST")
+	v1.HandleFunc("/people/enrich", handlers.StartReenrichJob).Methods("POST")
+	v1.HandleFunc("/people/enrich/{job:[0-9]+}", handlers.GetReenrichJob).Methods("GET")
+	v1.HandleFunc("/people/{id:[0-9]+}", handlers.GetPerson).Methods("GET")
+	v1.HandleFunc("/people/{id:[0-9]+}", handlers.UpdatePerson).Methods("PUT")
+	v1.HandleFunc("/people/{id:[0-9]+}", handlers.PatchPerson).Methods("PATCH")
+	v1.HandleFunc("/people/{id:[0-9]+}", handlers.DeletePerson).Methods("DELETE")
+	v1.HandleFunc("/people/{id:[0-9]+}/enrich", handlers.ReenrichPerson).Methods("POST")
+
+	// Устаревшие маршруты без версии, ID передаётся в query-параметре.
+	router.HandleFunc("/people", handlers.Deprecated("/api/v1/people", handlers.GetPeople)).Methods("GET")
+	router.HandleFunc("/people", handlers.Deprecated("/api/v1/people", handlers.CreatePerson)).Methods("POST")
+	router.HandleFunc("/people/bulk", handlers.Deprecated("/api/v1/people/bulk", handlers.CreatePeople)).Methods("POST")
+	router.HandleFunc("/people/enrichment", handlers.Deprecated("/api/v1/people/{id}", handlers.GetPerson)).Methods("GET")
+	router.HandleFunc("/people/enrich", handlers.Deprecated("/api/v1/people/enrich", handlers.StartReenrichJob)).Methods("POST")
+	router.HandleFunc("/people/enrich/{job:[0-9]+}", handlers.Deprecated("/api/v1/people/enrich/{job}", handlers.GetReenrichJob)).Methods("GET")
+	router.HandleFunc("/people/{id:[0-9]+}/enrich", handlers.Deprecated("/api/v1/people/{id}/enrich", handlers.ReenrichPerson)).Methods("POST")
+	router.HandleFunc("/people", handlers.Deprecated("/api/v1/people/{id}", handlers.UpdatePerson)).Methods("PUT")
+	router.HandleFunc("/people/{id:[0-9]+}", handlers.Deprecated("/api/v1/people/{id}", handlers.PatchPerson)).Methods("PATCH")
+	router.HandleFunc("/people", handlers.Deprecated("/api/v1/people/{id}", handlers.DeletePerson)).Methods("DELETE")
+
+	router.HandleFunc("/admin/enrichment/cache", handlers.GetEnrichmentCacheStats).Methods("GET")
+	router.HandleFunc("/admin/enrichment/breakers", handlers.GetEnrichmentBreakers).Methods("GET")
+	router.HandleFunc("/admin/enrichment/quota", handlers.GetEnrichmentQuota).Methods("GET")
 
 	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
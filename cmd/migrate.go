@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"task/config"
+	"task/repository"
+)
+
+const migrateUsage = "использование: migrate up | down [N] | status"
+
+// migrate выполняет подкоманду migrate для базы DB_DRIVER и возвращает код завершения.
+func migrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = config.DBDriver
+	}
+	r, err := repository.Open(driver)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка при подключении к базе данных:", err)
+		return 1
+	}
+	m, err := r.Migrator()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка при загрузке миграций:", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		done, err := m.Up(ctx)
+		for _, mg := range done {
+			fmt.Printf("применена %04d_%s\n", mg.Version, mg.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(done) == 0 {
+			fmt.Println("схема актуальна")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				fmt.Fprintln(os.Stderr, migrateUsage)
+				return 2
+			}
+		}
+		done, err := m.Down(ctx, steps)
+		for _, mg := range done {
+			fmt.Printf("откачена %04d_%s\n", mg.Version, mg.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, s := range status {
+			applied := "не применена"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, applied)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
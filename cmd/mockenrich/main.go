@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"task/config"
+	"task/internal"
+	"task/internal/mockenrich"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "адрес для прослушивания")
+	fixtures := flag.String("fixtures", "", "набор данных с ответами (JSON или CSV, как для OFFLINE_DATASET)")
+	latency := flag.Duration("latency", 0, "задержка перед каждым ответом")
+	errorRate := flag.Float64("error-rate", 0, "доля запросов, завершающихся ошибкой 500 (от 0 до 1)")
+	rateLimit := flag.Int("rate-limit", 0, "число имён на провайдера за окно (0 - без ограничений)")
+	rateWindow := flag.Duration("rate-window", 24*time.Hour, "длительность окна квоты")
+	flag.Parse()
+
+	config.LoadLoger()
+
+	cfg := mockenrich.Config{
+		Latency:    *latency,
+		ErrorRate:  *errorRate,
+		RateLimit:  *rateLimit,
+		RateWindow: *rateWindow,
+	}
+	if *fixtures != "" {
+		stats, err := internal.LoadNameStats(*fixtures)
+		if err != nil {
+			config.Logger.Fatal("Ошибка загрузки набора данных: ", err)
+		}
+		cfg.Fixtures = stats
+	}
+
+	config.Logger.Infoln("Запуск имитатора провайдеров обогащения на", *addr)
+	config.Logger.Fatal(http.ListenAndServe(*addr, mockenrich.New(cfg)))
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -12,6 +13,46 @@ var NationalizeURL string = "https://api.nationalize.io"
 var GenderizeURL string = "https://api.genderize.io"
 var AgifyURL string = "https://api.agify.io"
 
+var Enrichers string = "agify,genderize,nationalize,rules"
+
+var EnrichTimeout time.Duration = 10 * time.Second
+var EnrichCacheSize int = 1000
+var EnrichCacheTTL time.Duration = 24 * time.Hour
+var EnrichWorkers int = 4
+var EnrichQueueSize int = 1000
+var EnrichMaxAttempts int = 5
+var EnrichRetryDelay time.Duration = 2 * time.Second
+
+// EnrichSweepInterval - как часто записи в статусе pending, не попавшие в очередь, ставятся в неё повторно.
+var EnrichSweepInterval time.Duration = time.Minute
+
+var EnrichHTTPTimeout time.Duration = 5 * time.Second
+var EnrichMaxRetries int = 3
+var EnrichRetryBackoff time.Duration = 200 * time.Millisecond
+var EnrichRetryMaxBackoff time.Duration = 5 * time.Second
+var EnrichBreakerThreshold int = 5
+var EnrichBreakerCooldown time.Duration = 30 * time.Second
+var EnrichQuotaReserve int = 0
+
+// EnrichQuotaWindow - на сколько блокируются запросы после ответа 429 без Retry-After и X-Rate-Limit-Reset.
+var EnrichQuotaWindow time.Duration = time.Minute
+
+var EnrichWeight float64 = 1
+
+// ReenrichJobTTL - сколько хранится состояние завершённой задачи повторного обогащения.
+var ReenrichJobTTL time.Duration = time.Hour
+
+var EnrichTranslit string = "icao"
+
+var DBDriver string = "postgres"
+var DBPath string = "task.db"
+
+// DBAutoMigrate - применять ли миграции схемы при запуске сервера.
+var DBAutoMigrate bool = true
+
+// LegacyDeprecatedAt - дата, с которой маршруты без версии (/people) считаются устаревшими.
+var LegacyDeprecatedAt time.Time = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
 func LoadLoger() {
 
 	Logger = logrus.New()
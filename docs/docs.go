@@ -15,9 +15,75 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/people": {
+        "/admin/enrichment/breakers": {
             "get": {
-                "description": "Получение списка людей с фильтрацией по параметрам (id, name, surname, patronymic, age, gender, nationality) и пагинацией.",
+                "description": "Возвращает состояние автоматического выключателя (closed, open, half-open) и число подряд идущих сбоев для каждого провайдера обогащения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Состояние выключателей провайдеров",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal.BreakerStatus"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/enrichment/cache": {
+            "get": {
+                "description": "Возвращает количество попаданий и промахов кэша обогащения, а также текущий размер кэша в памяти.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Статистика кэша обогащения",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal.CacheStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/enrichment/quota": {
+            "get": {
+                "description": "Возвращает последние известные лимит и остаток запросов каждого провайдера (по заголовкам X-Rate-Limit-*) и время сброса квоты.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Квоты провайдеров обогащения",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal.QuotaStatus"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/people": {
+            "get": {
+                "description": "Получение списка людей с фильтрацией по параметрам и пагинацией. Строковые фильтры сравниваются способом match, параметры *_not исключают совпадения, gender и nationality принимают списки значений.",
                 "consumes": [
                     "application/json"
                 ],
@@ -53,6 +119,35 @@ const docTemplate = `{
                         "name": "patronymic",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Исключить людей с совпадающим именем",
+                        "name": "name_not",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Исключить людей с совпадающей фамилией",
+                        "name": "surname_not",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Исключить людей с совпадающим отчеством",
+                        "name": "patronymic_not",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "prefix",
+                            "contains"
+                        ],
+                        "type": "string",
+                        "description": "Способ сравнения строковых фильтров (по умолчанию contains)",
+                        "name": "match",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Возраст человека",
@@ -60,17 +155,49 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Пол человека",
+                        "type": "integer",
+                        "description": "Минимальный возраст включительно",
+                        "name": "age_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальный возраст включительно",
+                        "name": "age_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Пол человека, один или несколько через запятую: male, female, unknown или 0, 1, 2",
                         "name": "gender",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Национальность человека",
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Национальность (ISO 3166-1 alpha-2), одна или несколько через запятую",
                         "name": "nationality",
                         "in": "query"
                     },
+                    {
+                        "type": "number",
+                        "description": "Искать nationality среди всех стран-кандидатов с вероятностью не ниже указанной (от 0 до 1)",
+                        "name": "min_probability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Выражение RSQL/FIQL по полям id, name, surname, patronymic, age, gender, nationality, например age\u003e=30 and (nationality in ('RU','KZ') or gender=female)",
+                        "name": "filter",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Лимит записей (по умолчанию 10)",
@@ -94,6 +221,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Некорректные значения фильтров (application/problem+json)",
+                        "schema": {
+                            "$ref": "#/definitions/validation.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Ошибка сервера, например, при сбое подключения к базе данных",
                         "schema": {
@@ -105,8 +238,374 @@ const docTemplate = `{
                     }
                 }
             },
+            "post": {
+                "description": "Создает новую запись о человеке. По умолчанию запись сохраняется сразу со статусом обогащения pending, а обогащение выполняется в фоне с повторными попытками (статус можно получить через GET /api/v1/people/{id}). С флагом sync=true обогащение выполняется в рамках запроса.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Создание нового человека",
+                "parameters": [
+                    {
+                        "description": "Данные нового человека",
+                        "name": "person",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Person"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Выполнить обогащение синхронно",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код страны (ISO 3166-1 alpha-2) для уточнения возраста и пола",
+                        "name": "country_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Запись создана и обогащена (sync=true)",
+                        "schema": {
+                            "$ref": "#/definitions/models.Person"
+                        }
+                    },
+                    "202": {
+                        "description": "Запись создана, обогащение поставлено в очередь (в том числе при исчерпании квоты провайдера с sync=true)",
+                        "schema": {
+                            "$ref": "#/definitions/models.Person"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибки полей, в том числе body (некорректный JSON) и country_id (application/problem+json)",
+                        "schema": {
+                            "$ref": "#/definitions/validation.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при обогащении данных или сохранении в базу данных",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/people/bulk": {
+            "post": {
+                "description": "Создает несколько записей за один запрос. Обогащение выполняется пакетно: имена группируются, и каждый внешний API получает один запрос на группу. Переданные age, gender и nationality сохраняются с источником import и не перезаписываются обогащением.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Массовое создание людей",
+                "parameters": [
+                    {
+                        "description": "Список новых людей",
+                        "name": "people",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Person"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код страны (ISO 3166-1 alpha-2) для уточнения возраста и пола",
+                        "name": "country_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Person"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Квота провайдера исчерпана, записи сохранены и обогащение поставлено в очередь",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Person"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибки полей, в том числе body (некорректный JSON или пустой список) и country_id (application/problem+json)",
+                        "schema": {
+                            "$ref": "#/definitions/validation.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при обогащении данных или сохранении в базу данных",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/people/enrich": {
+            "post": {
+                "description": "Запускает фоновую задачу повторного обогащения всех людей, подходящих под фильтры (те же, что у GET /api/v1/people). Поля, заданные вручную через PUT или импортированные, сохраняются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Запуск массового повторного обогащения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID человека",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя человека",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фамилия человека",
+                        "name": "surname",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Отчество человека",
+                        "name": "patronymic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Исключить людей с совпадающим именем",
+                        "name": "name_not",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Исключить людей с совпадающей фамилией",
+                        "name": "surname_not",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Исключить людей с совпадающим отчеством",
+                        "name": "patronymic_not",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "prefix",
+                            "contains"
+                        ],
+                        "type": "string",
+                        "description": "Способ сравнения строковых фильтров (по умолчанию contains)",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Возраст человека",
+                        "name": "age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальный возраст включительно",
+                        "name": "age_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальный возраст включительно",
+                        "name": "age_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Пол человека, один или несколько через запятую: male, female, unknown или 0, 1, 2",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Национальность (ISO 3166-1 alpha-2), одна или несколько через запятую",
+                        "name": "nationality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Искать nationality среди всех стран-кандидатов с вероятностью не ниже указанной (от 0 до 1)",
+                        "name": "min_probability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Выражение RSQL/FIQL по полям id, name, surname, patronymic, age, gender, nationality, например age\u003e=30 and (nationality in ('RU','KZ') or gender=female)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код страны (ISO 3166-1 alpha-2) для уточнения возраста и пола",
+                        "name": "country_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/worker.ReenrichJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный country_id или значения фильтров (application/problem+json)",
+                        "schema": {
+                            "$ref": "#/definitions/validation.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/people/enrich/{job}": {
+            "get": {
+                "description": "Возвращает статус (running, done, failed) и прогресс задачи повторного обогащения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Состояние задачи повторного обогащения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "job",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/worker.ReenrichJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи (application/problem+json)",
+                        "schema": {
+                            "$ref": "#/definitions/validation.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена или удалена через час после завершения",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/people/{id}": {
+            "get": {
+                "description": "Возвращает запись о человеке вместе со статусом обогащения (pending, done, failed). Параметр wait позволяет дождаться завершения обогащения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Получение человека",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID человека",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Максимальное время ожидания завершения, не больше 10s, например 5s",
+                        "name": "wait",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Person"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный id, некорректное или превышающее 10s время ожидания wait (application/problem+json)",
+                        "schema": {
+                            "$ref": "#/definitions/validation.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Человек с указанным ID не найден",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
             "put": {
-                "description": "Обновляет данные существующего человека по ID. ID передается как query-параметр.",
+                "description": "Обновляет данные существующего человека по ID. Применяются все переданные поля.",
                 "consumes": [
                     "application/json"
                 ],
@@ -122,7 +621,7 @@ const docTemplate = `{
                         "type": "integer",
                         "description": "ID человека",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     },
                     {
@@ -146,12 +645,9 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Ошибка парсинга ID или JSON в теле запроса",
+                        "description": "Ошибки полей, в том числе id и body (некорректный JSON) (application/problem+json)",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/validation.Problem"
                         }
                     },
                     "404": {
@@ -174,8 +670,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Создает новую запись о человеке. При создании происходит обогащение данных через внешние API.",
+            "delete": {
+                "description": "Удаляет запись о человеке по ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -185,27 +681,100 @@ const docTemplate = `{
                 "tags": [
                     "people"
                 ],
-                "summary": "Создание нового человека",
+                "summary": "Удаление человека",
                 "parameters": [
                     {
-                        "description": "Данные нового человека",
-                        "name": "person",
+                        "type": "integer",
+                        "description": "ID человека",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Запись успешно удалена"
+                    },
+                    "400": {
+                        "description": "Некорректный id (application/problem+json)",
+                        "schema": {
+                            "$ref": "#/definitions/validation.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Человек с указанным ID не найден",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Изменяет только переданные поля. Тело в формате JSON Merge Patch (RFC 7396, Content-Type application/merge-patch+json или application/json): null очищает поле. Либо JSON Patch (RFC 6902, Content-Type application/json-patch+json): операции add, remove, replace, move, copy, test. Возраст, пол и национальность, заданные вручную, помечаются как manual.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Частичное обновление человека",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID человека",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON Merge Patch или JSON Patch",
+                        "name": "patch",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Person"
+                            "type": "object"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.Person"
                         }
                     },
                     "400": {
-                        "description": "Ошибка парсинга JSON в теле запроса",
+                        "description": "Ошибки полей, в том числе id и body (некорректный патч) (application/problem+json)",
+                        "schema": {
+                            "$ref": "#/definitions/validation.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Человек с указанным ID не найден",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый Content-Type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Патч нельзя применить, например не прошла операция test",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -214,7 +783,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Ошибка при обогащении данных или сохранении в базу данных",
+                        "description": "Ошибка при обновлении данных в базе",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -223,34 +792,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Удаляет запись о человеке по ID. ID передается как query-параметр.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/people/{id}/enrich": {
+            "post": {
+                "description": "Повторно запрашивает возраст, пол и национальность у провайдеров в обход кэша и обновляет запись. Поля, заданные вручную через PUT/PATCH или импортированные, сохраняются.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "people"
                 ],
-                "summary": "Удаление человека",
+                "summary": "Повторное обогащение человека",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "ID человека",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код страны (ISO 3166-1 alpha-2) для уточнения возраста и пола",
+                        "name": "country_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Запись успешно удалена"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Person"
+                        }
                     },
                     "400": {
-                        "description": "Ошибка парсинга ID",
+                        "description": "Некорректный id или country_id (application/problem+json)",
+                        "schema": {
+                            "$ref": "#/definitions/validation.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Человек с указанным ID не найден",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -258,8 +841,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Человек с указанным ID не найден",
+                    "409": {
+                        "description": "Имя или фамилия изменены во время обогащения, результат не сохранён",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при обогащении данных или сохранении в базу данных",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -272,6 +864,90 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "internal.BreakerState": {
+            "type": "string",
+            "enum": [
+                "closed",
+                "open",
+                "half-open"
+            ],
+            "x-enum-varnames": [
+                "BreakerClosed",
+                "BreakerOpen",
+                "BreakerHalfOpen"
+            ]
+        },
+        "internal.BreakerStatus": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "integer"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/internal.BreakerState"
+                }
+            }
+        },
+        "internal.CacheStats": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal.QuotaStatus": {
+            "type": "object",
+            "properties": {
+                "exhausted": {
+                    "type": "boolean"
+                },
+                "known": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "reset_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FullName": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "patronymic": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Gender": {
             "type": "integer",
             "enum": [
@@ -287,49 +963,205 @@ const docTemplate = `{
         },
         "models.Person": {
             "type": "object",
+            "required": [
+                "name",
+                "surname"
+            ],
             "properties": {
                 "age": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 150,
+                    "minimum": 0
+                },
+                "age_provenance": {
+                    "$ref": "#/definitions/models.Provenance"
+                },
+                "enrichment": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PersonEnrichment"
+                    }
+                },
+                "enrichment_error": {
+                    "type": "string"
+                },
+                "enrichment_status": {
+                    "type": "string"
                 },
                 "gender": {
-                    "$ref": "#/definitions/models.Gender"
+                    "maximum": 2,
+                    "minimum": 0,
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Gender"
+                        }
+                    ]
+                },
+                "gender_provenance": {
+                    "$ref": "#/definitions/models.Provenance"
                 },
                 "id": {
                     "type": "integer"
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 100
                 },
                 "nationality": {
                     "type": "string"
                 },
+                "nationality_provenance": {
+                    "$ref": "#/definitions/models.Provenance"
+                },
+                "original": {
+                    "description": "Original - имя, фамилия и отчество в том виде, в каком их передал клиент, до нормализации.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FullName"
+                        }
+                    ]
+                },
                 "patronymic": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 100
                 },
                 "surname": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "models.PersonEnrichment": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "fetched_at": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "probability": {
+                    "type": "number"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Provenance": {
+            "type": "object",
+            "properties": {
+                "set_at": {
+                    "type": "string"
+                },
+                "source": {
                     "type": "string"
                 }
             }
         },
         "models.UpdatePerson": {
             "type": "object",
+            "required": [
+                "name",
+                "surname"
+            ],
             "properties": {
                 "age": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 150,
+                    "minimum": 0
                 },
                 "gender": {
-                    "$ref": "#/definitions/models.Gender"
+                    "maximum": 2,
+                    "minimum": 0,
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Gender"
+                        }
+                    ]
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 100
                 },
                 "nationality": {
                     "type": "string"
                 },
                 "patronymic": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 100
                 },
                 "surname": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "validation.FieldError": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "validation.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "invalid-params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validation.FieldError"
+                    }
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "worker.ReenrichJob": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
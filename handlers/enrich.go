@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"task/config"
+	"task/internal"
+	"task/repository"
+	"task/validation"
+	"task/worker"
+
+	"github.com/gorilla/mux"
+)
+
+// ReenrichPerson godoc
+// @Summary Повторное обогащение человека
+// @Description Повторно запрашивает возраст, пол и национальность у провайдеров в обход кэша и обновляет запись. Поля, заданные вручную через PUT/PATCH или импортированные, сохраняются.
+// @Tags people
+// @Produce json
+// @Param id path int true "ID человека"
+// @Param country_id query string false "Код страны (ISO 3166-1 alpha-2) для уточнения возраста и пола"
+// @Success 200 {object} models.Person
+// @Failure 400 {object} validation.Problem "Некорректный id или country_id (application/problem+json)"
+// @Failure 404 {object} map[string]string "Человек с указанным ID не найден"
+// @Failure 409 {object} map[string]string "Имя или фамилия изменены во время обогащения, результат не сохранён"
+// @Failure 500 {object} map[string]string "Ошибка при обогащении данных или сохранении в базу данных"
+// @Router /api/v1/people/{id}/enrich [post]
+func ReenrichPerson(w http.ResponseWriter, r *http.Request) {
+	id, err := personID(r)
+	if err != nil {
+		config.Logger.Error("Ошибка парсинга id: ", err)
+		responseFieldError(w, r, "id", "must be an integer")
+		return
+	}
+
+	countryID, err := countryHint(r)
+	if err != nil {
+		config.Logger.Error("Ошибка парсинга country_id: ", err)
+		responseFieldError(w, r, "country_id", err.Error())
+		return
+	}
+
+	person, err := repository.GetPerson(id)
+	if err != nil {
+		config.Logger.Error("Ошибка поиска: ", err)
+		responseError(w, http.StatusNotFound, fmt.Errorf("record not found"))
+		return
+	}
+
+	q := internal.QueryFor(person, countryID)
+	res, err := internal.EnrichPerson(internal.WithRefresh(r.Context()), q)
+	if err != nil {
+		config.Logger.Error("Ошибка обогащения: ", err)
+		responseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	person, saved, err := worker.SaveReenriched(id, q, countryID, res)
+	if err != nil {
+		config.Logger.Error("Ошибка сохранения обогащения: ", err)
+		responseError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !saved {
+		if _, err := repository.GetPerson(id); errors.Is(err, repository.ErrNotFound) {
+			config.Logger.Infof("Запись с ID %d удалена во время обогащения", id)
+			responseError(w, http.StatusNotFound, fmt.Errorf("record not found"))
+			return
+		}
+		config.Logger.Infof("Запись с ID %d изменена во время обогащения, результат не сохранён", id)
+		responseError(w, http.StatusConflict, fmt.Errorf("record was modified during enrichment, retry the request"))
+		return
+	}
+
+	config.Logger.Infof("Успешно повторно обогащена запись с ID %d, сохранены заданные вручную поля: %v", id, internal.OverriddenFields(person))
+	response(w, http.StatusOK, person)
+}
+
+// StartReenrichJob godoc
+// @Summary Запуск массового повторного обогащения
+// @Description Запускает фоновую задачу повторного обогащения всех людей, подходящих под фильтры (те же, что у GET /api/v1/people). Поля, заданные вручную через PUT или импортированные, сохраняются.
+// @Tags people
+// @Produce json
+// @Param id query int false "ID человека"
+// @Param name query string false "Имя человека"
+// @Param surname query string false "Фамилия человека"
+// @Param patronymic query string false "Отчество человека"
+// @Param name_not query string false "Исключить людей с совпадающим именем"
+// @Param surname_not query string false "Исключить людей с совпадающей фамилией"
+// @Param patronymic_not query string false "Исключить людей с совпадающим отчеством"
+// @Param match query string false "Способ сравнения строковых фильтров (по умолчанию contains)" Enums(exact, prefix, contains)
+// @Param age query int false "Возраст человека"
+// @Param age_min query int false "Минимальный возраст включительно"
+// @Param age_max query int false "Максимальный возраст включительно"
+// @Param gender query []string false "Пол человека, один или несколько через запятую: male, female, unknown или 0, 1, 2" collectionFormat(csv)
+// @Param nationality query []string false "Национальность (ISO 3166-1 alpha-2), одна или несколько через запятую" collectionFormat(csv)
+// @Param min_probability query number false "Искать nationality среди всех стран-кандидатов с вероятностью не ниже указанной (от 0 до 1)"
+// @Param filter query string false "Выражение RSQL/FIQL по полям id, name, surname, patronymic, age, gender, nationality, например age>=30 and (nationality in ('RU','KZ') or gender=female)"
+// @Param country_id query string false "Код страны (ISO 3166-1 alpha-2) для уточнения возраста и пола"
+// @Success 202 {object} worker.ReenrichJob
+// @Failure 400 {object} validation.Problem "Некорректный country_id или значения фильтров (application/problem+json)"
+// @Router /api/v1/people/enrich [post]
+func StartReenrichJob(w http.ResponseWriter, r *http.Request) {
+	filter, errs := peopleFilter(r.URL.Query())
+	countryID, err := countryHint(r)
+	if err != nil {
+		errs = append(errs, validation.FieldError{Name: "country_id", Reason: err.Error()})
+	}
+	if len(errs) > 0 {
+		config.Logger.Error("Ошибка проверки фильтров: ", errs)
+		responseProblem(w, r, errs)
+		return
+	}
+
+	job := worker.StartReenrich(fetchPeople(filter), countryID)
+
+	config.Logger.Infof("Запущена задача повторного обогащения %d", job.Id)
+	response(w, http.StatusAccepted, job)
+}
+
+// GetReenrichJob godoc
+// @Summary Состояние задачи повторного обогащения
+// @Description Возвращает статус (running, done, failed) и прогресс задачи повторного обогащения.
+// @Tags people
+// @Produce json
+// @Param job path int true "ID задачи"
+// @Success 200 {object} worker.ReenrichJob
+// @Failure 400 {object} validation.Problem "Некорректный ID задачи (application/problem+json)"
+// @Failure 404 {object} map[string]string "Задача не найдена или удалена через час после завершения"
+// @Router /api/v1/people/enrich/{job} [get]
+func GetReenrichJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["job"])
+	if err != nil {
+		config.Logger.Error("Ошибка парсинга id задачи: ", err)
+		responseFieldError(w, r, "job", "must be an integer")
+		return
+	}
+
+	job, ok := worker.GetReenrich(id)
+	if !ok {
+		responseError(w, http.StatusNotFound, fmt.Errorf("job not found"))
+		return
+	}
+
+	response(w, http.StatusOK, job)
+}
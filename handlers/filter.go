@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"task/models"
+	"task/repository"
+	"task/rsql"
+	"task/validation"
+)
+
+// peopleFilter разбирает фильтры списка людей из query-параметров. Некорректные
+// значения возвращаются ошибками полей, а не игнорируются.
+func peopleFilter(q url.Values) (repository.PeopleFilter, validation.Errors) {
+	var filter repository.PeopleFilter
+	var errs validation.Errors
+
+	if s := q.Get("id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil || id <= 0 {
+			errs = append(errs, validation.FieldError{Name: "id", Reason: "must be a positive integer"})
+		}
+		filter.Id = id
+	}
+
+	filter.Name = strings.TrimSpace(q.Get("name"))
+	filter.Surname = strings.TrimSpace(q.Get("surname"))
+	filter.Patronymic = strings.TrimSpace(q.Get("patronymic"))
+	filter.NameNot = strings.TrimSpace(q.Get("name_not"))
+	filter.SurnameNot = strings.TrimSpace(q.Get("surname_not"))
+	filter.PatronymicNot = strings.TrimSpace(q.Get("patronymic_not"))
+	switch mode := repository.MatchMode(q.Get("match")); mode {
+	case "", repository.MatchContains, repository.MatchPrefix, repository.MatchExact:
+		filter.Match = mode
+	default:
+		errs = append(errs, validation.FieldError{Name: "match", Reason: "must be one of exact, prefix, contains"})
+	}
+
+	for _, age := range []struct {
+		param string
+		value *int
+	}{{"age", &filter.Age}, {"age_min", &filter.AgeMin}, {"age_max", &filter.AgeMax}} {
+		s := q.Get(age.param)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 || v > 150 {
+			errs = append(errs, validation.FieldError{Name: age.param, Reason: "must be an integer from 1 to 150"})
+			continue
+		}
+		*age.value = v
+	}
+	if filter.AgeMin != 0 && filter.AgeMax != 0 && filter.AgeMin > filter.AgeMax {
+		errs = append(errs, validation.FieldError{Name: "age_max", Reason: "must not be less than age_min"})
+	}
+
+	for _, s := range listParam(q, "gender") {
+		gender, ok := models.ParseGender(strings.ToLower(s))
+		if !ok {
+			errs = append(errs, validation.FieldError{Name: "gender", Reason: "must be one of male, female, unknown or 0, 1, 2"})
+			break
+		}
+		filter.Genders = append(filter.Genders, gender)
+	}
+	for _, s := range listParam(q, "nationality") {
+		country := strings.ToUpper(s)
+		if !isCountry(country) {
+			errs = append(errs, validation.FieldError{Name: "nationality", Reason: "must be a list of ISO 3166-1 alpha-2 codes"})
+			break
+		}
+		filter.Nationalities = append(filter.Nationalities, country)
+	}
+
+	if s := q.Get("min_probability"); s != "" {
+		p, err := strconv.ParseFloat(s, 64)
+		if err != nil || p < 0 || p > 1 {
+			errs = append(errs, validation.FieldError{Name: "min_probability", Reason: "must be a number from 0 to 1"})
+		}
+		filter.MinProbability = &p
+	}
+
+	if s := q.Get("filter"); s != "" {
+		expr, err := rsql.Parse(s, repository.PeopleFields)
+		if err != nil {
+			errs = append(errs, validation.FieldError{Name: "filter", Reason: err.Error()})
+		}
+		filter.Expr = expr
+	}
+	return filter, errs
+}
+
+// listParam возвращает значения параметра, переданного списком через запятую
+// (nationality=RU,UA) или повторением (nationality=RU&nationality=UA).
+func listParam(q url.Values, name string) []string {
+	var values []string
+	for _, v := range q[name] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// pagination разбирает limit (по умолчанию 10) и offset (по умолчанию 0).
+func pagination(q url.Values) (limit, offset int, errs validation.Errors) {
+	limit = 10
+	if s := q.Get("limit"); s != "" {
+		var err error
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			errs = append(errs, validation.FieldError{Name: "limit", Reason: "must be a positive integer"})
+		}
+	}
+	if s := q.Get("offset"); s != "" {
+		var err error
+		offset, err = strconv.Atoi(s)
+		if err != nil || offset < 0 {
+			errs = append(errs, validation.FieldError{Name: "offset", Reason: "must be a non-negative integer"})
+		}
+	}
+	return limit, offset, errs
+}
+
+func isCountry(s string) bool {
+	return len(s) == 2 && s[0] >= 'A' && s[0] <= 'Z' && s[1] >= 'A' && s[1] <= 'Z'
+}
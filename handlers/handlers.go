@@ -2,13 +2,21 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"task/config"
 	"task/internal"
+	"task/internal/names"
 	"task/models"
 	"task/repository"
+	"task/validation"
+	"task/worker"
+	"time"
+
+	"github.com/gorilla/mux"
 )
 
 func response(w http.ResponseWriter, code int, data any) {
@@ -26,9 +34,59 @@ func responseError(w http.ResponseWriter, code int, err error) {
 	response(w, code, map[string]string{"error": err.Error()})
 }
 
+// responseFieldError отвечает 400 в формате RFC 7807 с ошибкой одного поля или параметра,
+// например body, если тело запроса не разобралось как JSON.
+func responseFieldError(w http.ResponseWriter, r *http.Request, name, reason string) {
+	responseProblem(w, r, validation.Errors{{Name: name, Reason: reason}})
+}
+
+// responseProblem отвечает 400 со списком ошибок полей в формате RFC 7807.
+func responseProblem(w http.ResponseWriter, r *http.Request, errs validation.Errors) {
+	w.Header().Set("Content-Type", validation.ContentType)
+	w.WriteHeader(http.StatusBadRequest)
+	err := json.NewEncoder(w).Encode(errs.Problem(r.URL.Path))
+	if err != nil {
+		config.Logger.Error("Ошибка кодирования ответа: ", err)
+	}
+}
+
+// personID читает ID человека из пути (/api/v1/people/{id}) или, для устаревших
+// маршрутов, из query-параметра id.
+func personID(r *http.Request) (int, error) {
+	if id, ok := mux.Vars(r)["id"]; ok {
+		return strconv.Atoi(id)
+	}
+	return strconv.Atoi(r.URL.Query().Get("id"))
+}
+
+// countryHint читает необязательный код страны (ISO 3166-1 alpha-2) для обогащения.
+func countryHint(r *http.Request) (string, error) {
+	countryID := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("country_id")))
+	if countryID == "" {
+		return "", nil
+	}
+	if !isCountry(countryID) {
+		return "", fmt.Errorf("must be an ISO 3166-1 alpha-2 code")
+	}
+	return countryID, nil
+}
+
+// fetchPeople возвращает функцию постраничной загрузки людей по фильтру в порядке ID.
+func fetchPeople(filter repository.PeopleFilter) worker.FetchPage {
+	return func(afterID, limit int) ([]models.Person, error) {
+		filter.AfterId = afterID
+		return repository.GetPeople(filter, limit, 0)
+	}
+}
+
+const enrichmentPollInterval = 500 * time.Millisecond
+
+// maxEnrichmentWait ограничивает параметр wait, чтобы ответ успел уйти до WriteTimeout сервера (15s).
+const maxEnrichmentWait = 10 * time.Second
+
 // GetPeople godoc
 // @Summary Получение списка людей
-// @Description Получение списка людей с фильтрацией по параметрам (id, name, surname, patronymic, age, gender, nationality) и пагинацией.
+// @Description Получение списка людей с фильтрацией по параметрам и пагинацией. Строковые фильтры сравниваются способом match, параметры *_not исключают совпадения, gender и nationality принимают списки значений.
 // @Tags people
 // @Accept json
 // @Produce json
@@ -36,43 +94,33 @@ func responseError(w http.ResponseWriter, code int, err error) {
 // @Param name query string false "Имя человека"
 // @Param surname query string false "Фамилия человека"
 // @Param patronymic query string false "Отчество человека"
+// @Param name_not query string false "Исключить людей с совпадающим именем"
+// @Param surname_not query string false "Исключить людей с совпадающей фамилией"
+// @Param patronymic_not query string false "Исключить людей с совпадающим отчеством"
+// @Param match query string false "Способ сравнения строковых фильтров (по умолчанию contains)" Enums(exact, prefix, contains)
 // @Param age query int false "Возраст человека"
-// @Param gender query string false "Пол человека"
-// @Param nationality query string false "Национальность человека"
+// @Param age_min query int false "Минимальный возраст включительно"
+// @Param age_max query int false "Максимальный возраст включительно"
+// @Param gender query []string false "Пол человека, один или несколько через запятую: male, female, unknown или 0, 1, 2" collectionFormat(csv)
+// @Param nationality query []string false "Национальность (ISO 3166-1 alpha-2), одна или несколько через запятую" collectionFormat(csv)
+// @Param min_probability query number false "Искать nationality среди всех стран-кандидатов с вероятностью не ниже указанной (от 0 до 1)"
+// @Param filter query string false "Выражение RSQL/FIQL по полям id, name, surname, patronymic, age, gender, nationality, например age>=30 and (nationality in ('RU','KZ') or gender=female)"
 // @Param limit query int false "Лимит записей (по умолчанию 10)"
 // @Param offset query int false "Смещение для пагинации (по умолчанию 0)"
 // @Success 200 {array} models.Person
+// @Failure 400 {object} validation.Problem "Некорректные значения фильтров (application/problem+json)"
 // @Failure 500 {object} map[string]string "Ошибка сервера, например, при сбое подключения к базе данных"
-// @Router /people [get]
+// @Router /api/v1/people [get]
 func GetPeople(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Query().Get("id")
-	name := r.URL.Query().Get("name")
-	surname := r.URL.Query().Get("surname")
-	patronymic := r.URL.Query().Get("patronymic")
-	ageStr := r.URL.Query().Get("age")
-	gender := r.URL.Query().Get("gender")
-	nationality := r.URL.Query().Get("nationality")
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := 10
-	offset := 0
-
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
-	}
-
-	if offsetStr != "" {
-		parsedOffset, err := strconv.Atoi(offsetStr)
-		if err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
-		}
+	filter, errs := peopleFilter(r.URL.Query())
+	limit, offset, pageErrs := pagination(r.URL.Query())
+	if errs = append(errs, pageErrs...); len(errs) > 0 {
+		config.Logger.Error("Ошибка проверки фильтров: ", errs)
+		responseProblem(w, r, errs)
+		return
 	}
 
-	people, err := repository.GetPeople(id, name, surname, patronymic, ageStr, gender, nationality, limit, offset)
+	people, err := repository.GetPeople(filter, limit, offset)
 	if err != nil {
 		config.Logger.Error("Ошибка получения данных: ", err)
 		responseError(w, http.StatusInternalServerError, err)
@@ -85,38 +133,66 @@ func GetPeople(w http.ResponseWriter, r *http.Request) {
 
 // CreatePerson godoc
 // @Summary Создание нового человека
-// @Description Создает новую запись о человеке. При создании происходит обогащение данных через внешние API.
+// @Description Создает новую запись о человеке. По умолчанию запись сохраняется сразу со статусом обогащения pending, а обогащение выполняется в фоне с повторными попытками (статус можно получить через GET /api/v1/people/{id}). С флагом sync=true обогащение выполняется в рамках запроса.
 // @Tags people
 // @Accept json
 // @Produce json
 // @Param person body models.Person true "Данные нового человека"
-// @Success 201 {object} models.Person
-// @Failure 400 {object} map[string]string "Ошибка парсинга JSON в теле запроса"
+// @Param sync query bool false "Выполнить обогащение синхронно"
+// @Param country_id query string false "Код страны (ISO 3166-1 alpha-2) для уточнения возраста и пола"
+// @Success 201 {object} models.Person "Запись создана и обогащена (sync=true)"
+// @Success 202 {object} models.Person "Запись создана, обогащение поставлено в очередь (в том числе при исчерпании квоты провайдера с sync=true)"
+// @Failure 400 {object} validation.Problem "Ошибки полей, в том числе body (некорректный JSON) и country_id (application/problem+json)"
 // @Failure 500 {object} map[string]string "Ошибка при обогащении данных или сохранении в базу данных"
-// @Router /people [post]
+// @Router /api/v1/people [post]
 func CreatePerson(w http.ResponseWriter, r *http.Request) {
 	var input models.Person
 
 	err := json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
 		config.Logger.Error("Ошибка парсинга входных данных: ", err)
-		responseError(w, http.StatusBadRequest, err)
+		responseFieldError(w, r, "body", err.Error())
 		return
 	}
 	defer r.Body.Close()
 
-	age, gender, nationality, err := internal.EnrichPerson(input.Name)
+	if err := validation.Validate(input); err != nil {
+		config.Logger.Error("Ошибка проверки входных данных: ", err)
+		responseProblem(w, r, err.(validation.Errors))
+		return
+	}
+
+	names.Apply(&input)
+
+	countryID, err := countryHint(r)
+	if err != nil {
+		config.Logger.Error("Ошибка парсинга country_id: ", err)
+		responseFieldError(w, r, "country_id", err.Error())
+		return
+	}
+
+	sync, _ := strconv.ParseBool(r.URL.Query().Get("sync"))
+	if !sync {
+		createPersonAsync(w, r, input, countryID)
+		return
+	}
+
+	enriched, err := internal.EnrichPerson(r.Context(), internal.QueryFor(input, countryID))
+	if errors.Is(err, internal.ErrQuotaExhausted) {
+		config.Logger.Warn("Квота провайдера исчерпана, обогащение выполняется асинхронно: ", err)
+		createPersonAsync(w, r, input, countryID)
+		return
+	}
 	if err != nil {
 		config.Logger.Error("Ошибка обогащения: ", err)
 		responseError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	input.Age = age
-	input.Gender = gender
-	input.Nationality = nationality
+	enriched.Apply(&input)
+	input.EnrichmentStatus = models.EnrichmentDone
 
-	err = repository.CreatePerson(input)
+	err = repository.CreatePerson(&input)
 	if err != nil {
 		config.Logger.Error("Ошибка сохранения данных в БД: ", err)
 		responseError(w, http.StatusInternalServerError, err)
@@ -127,59 +203,250 @@ func CreatePerson(w http.ResponseWriter, r *http.Request) {
 	response(w, http.StatusCreated, input)
 }
 
+func createPersonAsync(w http.ResponseWriter, r *http.Request, input models.Person, countryID string) {
+	input.EnrichmentStatus = models.EnrichmentPending
+	input.Enrichment = nil
+	for _, field := range models.InferredFields {
+		*input.FieldProvenance(field) = models.Provenance{}
+	}
+
+	err := repository.CreatePerson(&input)
+	if err != nil {
+		config.Logger.Error("Ошибка сохранения данных в БД: ", err)
+		responseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = worker.Enqueue(input, countryID)
+	if err != nil {
+		config.Logger.Warnf("Запись с ID %d не поставлена в очередь обогащения и будет обработана при следующей проверке ожидающих записей: %v", input.Id, err)
+	}
+
+	config.Logger.Infof("Успешно создана запись для %s %s, обогащение поставлено в очередь", input.Name, input.Surname)
+	response(w, http.StatusAccepted, input)
+}
+
+// GetPerson godoc
+// @Summary Получение человека
+// @Description Возвращает запись о человеке вместе со статусом обогащения (pending, done, failed). Параметр wait позволяет дождаться завершения обогащения.
+// @Tags people
+// @Produce json
+// @Param id path int true "ID человека"
+// @Param wait query string false "Максимальное время ожидания завершения, не больше 10s, например 5s"
+// @Success 200 {object} models.Person
+// @Failure 400 {object} validation.Problem "Некорректный id, некорректное или превышающее 10s время ожидания wait (application/problem+json)"
+// @Failure 404 {object} map[string]string "Человек с указанным ID не найден"
+// @Router /api/v1/people/{id} [get]
+func GetPerson(w http.ResponseWriter, r *http.Request) {
+	id, err := personID(r)
+	if err != nil {
+		config.Logger.Error("Ошибка парсинга id: ", err)
+		responseFieldError(w, r, "id", "must be an integer")
+		return
+	}
+
+	var wait time.Duration
+	if waitStr := r.URL.Query().Get("wait"); waitStr != "" {
+		wait, err = time.ParseDuration(waitStr)
+		if err != nil {
+			config.Logger.Error("Ошибка парсинга wait: ", err)
+			responseFieldError(w, r, "wait", "must be a duration, for example 5s")
+			return
+		}
+		if wait < 0 || wait > maxEnrichmentWait {
+			responseFieldError(w, r, "wait", fmt.Sprintf("must be between 0s and %s", maxEnrichmentWait))
+			return
+		}
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		person, err := repository.GetPerson(id)
+		if err != nil {
+			config.Logger.Error("Ошибка поиска: ", err)
+			responseError(w, http.StatusNotFound, fmt.Errorf("record not found"))
+			return
+		}
+
+		if person.EnrichmentStatus != models.EnrichmentPending || !time.Now().Before(deadline) {
+			response(w, http.StatusOK, person)
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(enrichmentPollInterval):
+		}
+	}
+}
+
+// CreatePeople godoc
+// @Summary Массовое создание людей
+// @Description Создает несколько записей за один запрос. Обогащение выполняется пакетно: имена группируются, и каждый внешний API получает один запрос на группу. Переданные age, gender и nationality сохраняются с источником import и не перезаписываются обогащением.
+// @Tags people
+// @Accept json
+// @Produce json
+// @Param people body []models.Person true "Список новых людей"
+// @Param country_id query string false "Код страны (ISO 3166-1 alpha-2) для уточнения возраста и пола"
+// @Success 201 {array} models.Person
+// @Success 202 {array} models.Person "Квота провайдера исчерпана, записи сохранены и обогащение поставлено в очередь"
+// @Failure 400 {object} validation.Problem "Ошибки полей, в том числе body (некорректный JSON или пустой список) и country_id (application/problem+json)"
+// @Failure 500 {object} map[string]string "Ошибка при обогащении данных или сохранении в базу данных"
+// @Router /api/v1/people/bulk [post]
+func CreatePeople(w http.ResponseWriter, r *http.Request) {
+	var input []models.Person
+
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		config.Logger.Error("Ошибка парсинга входных данных: ", err)
+		responseFieldError(w, r, "body", err.Error())
+		return
+	}
+	defer r.Body.Close()
+
+	if len(input) == 0 {
+		responseFieldError(w, r, "body", "must not be empty")
+		return
+	}
+
+	var invalid validation.Errors
+	for i := range input {
+		if err := validation.ValidateImport(input[i]); err != nil {
+			invalid = append(invalid, err.(validation.Errors).Prefix(fmt.Sprintf("[%d]", i))...)
+		}
+	}
+	if len(invalid) > 0 {
+		config.Logger.Error("Ошибка проверки входных данных: ", invalid)
+		responseProblem(w, r, invalid)
+		return
+	}
+
+	for i := range input {
+		names.Apply(&input[i])
+		markImported(&input[i])
+	}
+
+	countryID, err := countryHint(r)
+	if err != nil {
+		config.Logger.Error("Ошибка парсинга country_id: ", err)
+		responseFieldError(w, r, "country_id", err.Error())
+		return
+	}
+
+	queries := make([]internal.Query, len(input))
+	for i, p := range input {
+		queries[i] = internal.QueryFor(p, countryID)
+	}
+
+	enriched, err := internal.EnrichBatch(r.Context(), queries)
+	if errors.Is(err, internal.ErrQuotaExhausted) {
+		config.Logger.Warn("Квота провайдера исчерпана, обогащение выполняется асинхронно: ", err)
+		createPeopleAsync(w, r, input, countryID)
+		return
+	}
+	if err != nil {
+		config.Logger.Error("Ошибка обогащения: ", err)
+		responseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for i := range input {
+		enriched[i].Reapply(&input[i])
+		input[i].EnrichmentStatus = models.EnrichmentDone
+	}
+
+	err = repository.CreatePeople(input)
+	if err != nil {
+		config.Logger.Error("Ошибка сохранения данных в БД: ", err)
+		responseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	config.Logger.Infof("Успешно создано %d записей", len(input))
+	response(w, http.StatusCreated, input)
+}
+
+// markImported помечает вычисляемые поля, переданные при массовом создании, как
+// импортированные, чтобы обогащение их не перезаписывало.
+func markImported(p *models.Person) {
+	for _, field := range models.InferredFields {
+		provenance := p.FieldProvenance(field)
+		*provenance = models.Provenance{}
+		if p.FieldValue(field) != (models.Person{}).FieldValue(field) {
+			*provenance = models.NewProvenance(models.SourceImport)
+		}
+	}
+}
+
+func createPeopleAsync(w http.ResponseWriter, r *http.Request, input []models.Person, countryID string) {
+	for i := range input {
+		input[i].EnrichmentStatus = models.EnrichmentPending
+		input[i].Enrichment = nil
+	}
+
+	err := repository.CreatePeople(input)
+	if err != nil {
+		config.Logger.Error("Ошибка сохранения данных в БД: ", err)
+		responseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, p := range input {
+		if err := worker.Enqueue(p, countryID); err != nil {
+			config.Logger.Warnf("Запись с ID %d не поставлена в очередь обогащения и будет обработана при следующей проверке ожидающих записей: %v", p.Id, err)
+		}
+	}
+
+	config.Logger.Infof("Успешно создано %d записей, обогащение поставлено в очередь", len(input))
+	response(w, http.StatusAccepted, input)
+}
+
 // UpdatePerson godoc
 // @Summary Обновление данных человека
-// @Description Обновляет данные существующего человека по ID. ID передается как query-параметр.
+// @Description Обновляет данные существующего человека по ID. Применяются все переданные поля.
 // @Tags people
 // @Accept json
 // @Produce json
-// @Param id query int true "ID человека"
+// @Param id path int true "ID человека"
 // @Param person body models.UpdatePerson true "Данные для обновления"
 // @Success 200 {object} map[string]string "Обновление успешно выполнено"
-// @Failure 400 {object} map[string]string "Ошибка парсинга ID или JSON в теле запроса"
+// @Failure 400 {object} validation.Problem "Ошибки полей, в том числе id и body (некорректный JSON) (application/problem+json)"
 // @Failure 404 {object} map[string]string "Человек с указанным ID не найден"
 // @Failure 500 {object} map[string]string "Ошибка при обновлении данных в базе"
-// @Router /people [put]
+// @Router /api/v1/people/{id} [put]
 func UpdatePerson(w http.ResponseWriter, r *http.Request) {
 	input := new(models.UpdatePerson)
 
 	err := json.NewDecoder(r.Body).Decode(input)
 	if err != nil {
 		config.Logger.Error("Ошибка парсинга входных данных: ", err)
-		responseError(w, http.StatusBadRequest, err)
+		responseFieldError(w, r, "body", err.Error())
 		return
 	}
 	defer r.Body.Close()
 
-	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err := validation.Validate(input); err != nil {
+		config.Logger.Error("Ошибка проверки входных данных: ", err)
+		responseProblem(w, r, err.(validation.Errors))
+		return
+	}
+
+	id, err := personID(r)
 	if err != nil {
 		config.Logger.Error("Ошибка парсинга id: ", err)
-		responseError(w, http.StatusBadRequest, err)
+		responseFieldError(w, r, "id", "must be an integer")
 		return
 	}
 
-	existed, err := repository.GetPeople(strconv.Itoa(id), "", "", "", "", "", "", 1, 0)
-	if err != nil || len(existed) == 0 {
+	exist, err := repository.GetPerson(id)
+	if err != nil {
 		config.Logger.Error("Ошибка поиска: ", err)
 		responseError(w, http.StatusNotFound, fmt.Errorf("record not found"))
 		return
 	}
 
-	exist := existed[0]
-	switch {
-	case input.Name != nil:
-		exist.Name = *input.Name
-	case input.Surname != nil:
-		exist.Surname = *input.Surname
-	case input.Patronymic != nil:
-		exist.Patronymic = *input.Patronymic
-	case input.Age != nil:
-		exist.Age = *input.Age
-	case input.Gender != nil:
-		exist.Gender = *input.Gender
-	case input.Nationality != nil:
-		exist.Nationality = *input.Nationality
-	}
+	applyUpdate(&exist, *input)
 
 	err = repository.UpdatePerson(exist)
 	if err != nil {
@@ -192,22 +459,51 @@ func UpdatePerson(w http.ResponseWriter, r *http.Request) {
 	response(w, http.StatusOK, nil)
 }
 
+// applyUpdate переносит в запись все переданные поля. Возраст, пол и национальность,
+// заданные вручную, помечаются как manual и не перезаписываются обогащением.
+func applyUpdate(p *models.Person, input models.UpdatePerson) {
+	if input.Name != nil {
+		p.Name = names.Normalize(*input.Name)
+		p.Original.Name = *input.Name
+	}
+	if input.Surname != nil {
+		p.Surname = names.Normalize(*input.Surname)
+		p.Original.Surname = *input.Surname
+	}
+	if input.Patronymic != nil {
+		p.Patronymic = names.Normalize(*input.Patronymic)
+		p.Original.Patronymic = *input.Patronymic
+	}
+	if input.Age != nil {
+		p.Age = *input.Age
+		p.AgeProvenance = models.NewProvenance(models.SourceManual)
+	}
+	if input.Gender != nil {
+		p.Gender = *input.Gender
+		p.GenderProvenance = models.NewProvenance(models.SourceManual)
+	}
+	if input.Nationality != nil {
+		p.Nationality = *input.Nationality
+		p.NationalityProvenance = models.NewProvenance(models.SourceManual)
+	}
+}
+
 // DeletePerson godoc
 // @Summary Удаление человека
-// @Description Удаляет запись о человеке по ID. ID передается как query-параметр.
+// @Description Удаляет запись о человеке по ID.
 // @Tags people
 // @Accept json
 // @Produce json
-// @Param id query int true "ID человека"
+// @Param id path int true "ID человека"
 // @Success 204 "Запись успешно удалена"
-// @Failure 400 {object} map[string]string "Ошибка парсинга ID"
+// @Failure 400 {object} validation.Problem "Некорректный id (application/problem+json)"
 // @Failure 404 {object} map[string]string "Человек с указанным ID не найден"
-// @Router /people [delete]
+// @Router /api/v1/people/{id} [delete]
 func DeletePerson(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	id, err := personID(r)
 	if err != nil {
 		config.Logger.Error("Ошибка парсинга id: ", err)
-		responseError(w, http.StatusBadRequest, err)
+		responseFieldError(w, r, "id", "must be an integer")
 		return
 	}
 
@@ -221,3 +517,36 @@ func DeletePerson(w http.ResponseWriter, r *http.Request) {
 	config.Logger.Infof("Успешно удалена запись с ID %d", id)
 	response(w, http.StatusNoContent, nil)
 }
+
+// GetEnrichmentCacheStats godoc
+// @Summary Статистика кэша обогащения
+// @Description Возвращает количество попаданий и промахов кэша обогащения, а также текущий размер кэша в памяти.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} internal.CacheStats
+// @Router /admin/enrichment/cache [get]
+func GetEnrichmentCacheStats(w http.ResponseWriter, r *http.Request) {
+	response(w, http.StatusOK, internal.Stats())
+}
+
+// GetEnrichmentBreakers godoc
+// @Summary Состояние выключателей провайдеров
+// @Description Возвращает состояние автоматического выключателя (closed, open, half-open) и число подряд идущих сбоев для каждого провайдера обогащения.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} internal.BreakerStatus
+// @Router /admin/enrichment/breakers [get]
+func GetEnrichmentBreakers(w http.ResponseWriter, r *http.Request) {
+	response(w, http.StatusOK, internal.BreakerStates())
+}
+
+// GetEnrichmentQuota godoc
+// @Summary Квоты провайдеров обогащения
+// @Description Возвращает последние известные лимит и остаток запросов каждого провайдера (по заголовкам X-Rate-Limit-*) и время сброса квоты.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} internal.QuotaStatus
+// @Router /admin/enrichment/quota [get]
+func GetEnrichmentQuota(w http.ResponseWriter, r *http.Request) {
+	response(w, http.StatusOK, internal.QuotaStates())
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"task/config"
+	"task/internal"
+	"task/models"
+	"task/repository"
+	"task/validation"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestMain(m *testing.M) {
+	config.LoadLoger()
+	male := 1.0
+	internal.Register(internal.NewOffline([]internal.NameStats{{
+		Name:      "Иван",
+		Ages:      map[int]int{40: 1},
+		Male:      &male,
+		Countries: map[string]float64{"RU": 0.9},
+	}}))
+	os.Exit(m.Run())
+}
+
+func testRouter() *mux.Router {
+	repository.Use(repository.NewMemory())
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/people", CreatePerson).Methods("POST")
+	router.HandleFunc("/api/v1/people/{id:[0-9]+}", GetPerson).Methods("GET")
+	router.HandleFunc("/api/v1/people/{id:[0-9]+}", UpdatePerson).Methods("PUT")
+	router.HandleFunc("/api/v1/people/{id:[0-9]+}", DeletePerson).Methods("DELETE")
+	router.HandleFunc("/api/v1/people/{id:[0-9]+}/enrich", ReenrichPerson).Methods("POST")
+	router.HandleFunc("/api/v1/people/enrich/{job:[0-9]+}", GetReenrichJob).Methods("GET")
+	router.HandleFunc("/people/enrichment", GetPerson).Methods("GET")
+	router.HandleFunc("/people", DeletePerson).Methods("DELETE")
+	return router
+}
+
+func do(t *testing.T, router http.Handler, method, url, body string, want int) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, url, strings.NewReader(body)))
+	if w.Code != want {
+		t.Fatalf("%s %s: status %d, want %d: %s", method, url, w.Code, want, w.Body)
+	}
+	return w
+}
+
+func decodePerson(t *testing.T, w *httptest.ResponseRecorder) models.Person {
+	t.Helper()
+	var p models.Person
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestReenrichKeepsManualOverride(t *testing.T) {
+	router := testRouter()
+
+	created := decodePerson(t, do(t, router, "POST", "/api/v1/people?sync=true", `{"name":"  иван ","surname":"петров"}`, http.StatusCreated))
+	if created.Name != "Иван" || created.Original.Name != "  иван " {
+		t.Errorf("name = %q, original = %q", created.Name, created.Original.Name)
+	}
+	if created.Age != 40 || created.Gender != models.Male || created.Nationality != "RU" {
+		t.Fatalf("created without enrichment: %+v", created)
+	}
+
+	do(t, router, "PUT", "/api/v1/people/1", `{"age":25}`, http.StatusOK)
+	reenriched := decodePerson(t, do(t, router, "POST", "/api/v1/people/1/enrich", "", http.StatusOK))
+
+	for _, p := range []models.Person{reenriched, decodePerson(t, do(t, router, "GET", "/api/v1/people/1", "", http.StatusOK))} {
+		if p.Age != 25 || p.AgeProvenance.Source != models.SourceManual {
+			t.Errorf("age = %d (%s), want manual 25", p.Age, p.AgeProvenance.Source)
+		}
+		if p.Gender != models.Male || p.GenderProvenance.Source == models.SourceManual {
+			t.Errorf("gender = %s (%s), want enriched male", p.Gender, p.GenderProvenance.Source)
+		}
+		if p.EnrichmentStatus != models.EnrichmentDone {
+			t.Errorf("status = %s, want done", p.EnrichmentStatus)
+		}
+	}
+}
+
+// hookEnricher вызывает hook во время запроса, имитируя медленного провайдера,
+// пока запись меняют через API.
+type hookEnricher struct {
+	hook func()
+}
+
+func (hookEnricher) Name() string { return "hook" }
+
+func (e hookEnricher) Enrich(ctx context.Context, q internal.Query) (internal.Result, error) {
+	e.hook()
+	return internal.Result{}, nil
+}
+
+func TestReenrichKeepsChangesMadeDuringEnrichment(t *testing.T) {
+	router := testRouter()
+	do(t, router, "POST", "/api/v1/people?sync=true", `{"name":"Иван","surname":"Петров"}`, http.StatusCreated)
+	defer internal.Unregister("hook")
+
+	internal.Register(hookEnricher{hook: func() {
+		do(t, router, "PUT", "/api/v1/people/1", `{"age":25}`, http.StatusOK)
+	}})
+	reenriched := decodePerson(t, do(t, router, "POST", "/api/v1/people/1/enrich", "", http.StatusOK))
+	if reenriched.Age != 25 || reenriched.AgeProvenance.Source != models.SourceManual {
+		t.Errorf("age = %d (%s), want manual 25", reenriched.Age, reenriched.AgeProvenance.Source)
+	}
+
+	// Результат для старой фамилии не сохраняется.
+	internal.Register(hookEnricher{hook: func() {
+		do(t, router, "PUT", "/api/v1/people/1", `{"surname":"Сидоров"}`, http.StatusOK)
+	}})
+	do(t, router, "POST", "/api/v1/people/1/enrich", "", http.StatusConflict)
+	if p := decodePerson(t, do(t, router, "GET", "/api/v1/people/1", "", http.StatusOK)); p.Surname != "Сидоров" || p.Age != 25 {
+		t.Errorf("record after conflict = %+v", p)
+	}
+}
+
+func TestCreatePersonDegradesWhenQuotaExhausted(t *testing.T) {
+	router := testRouter()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	t.Setenv("AGIFY_RETRY_BACKOFF", "1ms")
+	internal.Register(internal.NewAgify(srv.URL))
+	defer internal.Unregister("agify")
+
+	for i, name := range []string{"Иван", "Пётр"} {
+		w := do(t, router, "POST", "/api/v1/people?sync=true", `{"name":"`+name+`","surname":"Петров"}`, http.StatusAccepted)
+		if p := decodePerson(t, w); p.EnrichmentStatus != models.EnrichmentPending {
+			t.Errorf("%s: status = %s, want pending", name, p.EnrichmentStatus)
+		}
+		// После первого ответа 429 запросы к провайдеру не отправляются до сброса квоты.
+		if calls.Load() != 1 {
+			t.Errorf("request %d: provider called %d times, want 1", i+1, calls.Load())
+		}
+	}
+}
+
+// problemField проверяет ответ в формате application/problem+json с одним полем и возвращает его имя.
+func problemField(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != validation.ContentType {
+		t.Errorf("Content-Type %q, want %q", ct, validation.ContentType)
+	}
+	var problem validation.Problem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	if len(problem.InvalidParams) != 1 {
+		t.Fatalf("invalid-params %v, want one field", problem.InvalidParams)
+	}
+	return problem.InvalidParams[0].Name
+}
+
+func TestCreatePersonProblems(t *testing.T) {
+	router := testRouter()
+
+	tests := []struct {
+		url, body, field string
+	}{
+		{"/api/v1/people", `{"name":`, "body"},
+		{"/api/v1/people?country_id=R1", `{"name":"Иван","surname":"Петров"}`, "country_id"},
+		{"/api/v1/people", `{"name":"Иван","surname":"Петров","age":30}`, "age"},
+		{"/api/v1/people", `{"name":"Иван2","surname":"Петров"}`, "name"},
+		{"/api/v1/people", `{"name":"Иван","surname":"Петров","enrichment":[{"provider":"agify"}]}`, "enrichment"},
+	}
+	for _, tt := range tests {
+		w := do(t, router, "POST", tt.url, tt.body, http.StatusBadRequest)
+		if field := problemField(t, w); field != tt.field {
+			t.Errorf("%s %s: invalid field %s, want %s", tt.url, tt.body, field, tt.field)
+		}
+	}
+
+	// Пустые списки равнозначны отсутствующему полю.
+	do(t, router, "POST", "/api/v1/people", `{"name":"Иван","surname":"Петров","enrichment":[]}`, http.StatusAccepted)
+}
+
+func TestParameterProblems(t *testing.T) {
+	router := testRouter()
+
+	tests := []struct {
+		method, url, field string
+	}{
+		{"GET", "/people/enrichment?id=abc", "id"},
+		{"DELETE", "/people?id=abc", "id"},
+		{"GET", "/api/v1/people/1?wait=soon", "wait"},
+		{"GET", "/api/v1/people/1?wait=1m", "wait"},
+		{"GET", "/api/v1/people/1?wait=-1s", "wait"},
+		{"GET", "/api/v1/people/enrich/99999999999999999999", "job"},
+	}
+	for _, tt := range tests {
+		w := do(t, router, tt.method, tt.url, "", http.StatusBadRequest)
+		if field := problemField(t, w); field != tt.field {
+			t.Errorf("%s %s: invalid field %s, want %s", tt.method, tt.url, field, tt.field)
+		}
+	}
+}
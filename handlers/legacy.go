@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"task/config"
+
+	"github.com/gorilla/mux"
+)
+
+// Deprecated оборачивает обработчик устаревшего маршрута без версии. Ответ дополняется
+// заголовками Deprecation (RFC 9745), Link с rel="successor-version" и, если задан
+// LEGACY_SUNSET, Sunset (RFC 8594). В successor подстановки {id} и {job} заменяются
+// параметрами запроса: из пути или, для старых маршрутов, из query-параметра id.
+func Deprecated(successor string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		link := successor
+		for _, key := range []string{"id", "job"} {
+			value := mux.Vars(r)[key]
+			if value == "" {
+				value = r.URL.Query().Get(key)
+			}
+			link = strings.ReplaceAll(link, "{"+key+"}", url.PathEscape(value))
+		}
+
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", config.LegacyDeprecatedAt.Unix()))
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", link))
+		if sunset := os.Getenv("LEGACY_SUNSET"); sunset != "" {
+			w.Header().Set("Sunset", sunset)
+		}
+
+		config.Logger.Warnf("Вызов устаревшего маршрута %s %s, замена: %s", r.Method, r.URL.Path, link)
+		next(w, r)
+	}
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"task/config"
+	"task/models"
+	"task/patch"
+	"task/repository"
+	"task/validation"
+)
+
+// maxPatchSize ограничивает размер тела PATCH-запроса.
+const maxPatchSize = 1 << 20
+
+// patchableFields - поля Person, которые клиент может изменять, и их значения
+// после удаления (null в Merge Patch, remove в JSON Patch).
+var patchableFields = map[string]any{
+	"name":        "",
+	"surname":     "",
+	"patronymic":  "",
+	"age":         0,
+	"gender":      0,
+	"nationality": "",
+}
+
+// PatchPerson godoc
+// @Summary Частичное обновление человека
+// @Description Изменяет только переданные поля. Тело в формате JSON Merge Patch (RFC 7396, Content-Type application/merge-patch+json или application/json): null очищает поле. Либо JSON Patch (RFC 6902, Content-Type application/json-patch+json): операции add, remove, replace, move, copy, test. Возраст, пол и национальность, заданные вручную, помечаются как manual.
+// @Tags people
+// @Accept json
+// @Produce json
+// @Param id path int true "ID человека"
+// @Param patch body object true "JSON Merge Patch или JSON Patch"
+// @Success 200 {object} models.Person
+// @Failure 400 {object} validation.Problem "Ошибки полей, в том числе id и body (некорректный патч) (application/problem+json)"
+// @Failure 404 {object} map[string]string "Человек с указанным ID не найден"
+// @Failure 415 {object} map[string]string "Неподдерживаемый Content-Type"
+// @Failure 422 {object} map[string]string "Патч нельзя применить, например не прошла операция test"
+// @Failure 500 {object} map[string]string "Ошибка при обновлении данных в базе"
+// @Router /api/v1/people/{id} [patch]
+func PatchPerson(w http.ResponseWriter, r *http.Request) {
+	id, err := personID(r)
+	if err != nil {
+		config.Logger.Error("Ошибка парсинга id: ", err)
+		responseFieldError(w, r, "id", "must be an integer")
+		return
+	}
+
+	apply := patch.Merge
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		switch {
+		case err != nil:
+			responseError(w, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content type: %s", contentType))
+			return
+		case mediaType == patch.JSONPatchType:
+			apply = patch.Apply
+		case mediaType != patch.MergePatchType && mediaType != "application/json":
+			responseError(w, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content type: %s", mediaType))
+			return
+		}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchSize))
+	if err != nil {
+		config.Logger.Error("Ошибка чтения тела запроса: ", err)
+		responseFieldError(w, r, "body", err.Error())
+		return
+	}
+	defer r.Body.Close()
+
+	exist, err := repository.GetPerson(id)
+	if err != nil {
+		config.Logger.Error("Ошибка поиска: ", err)
+		responseError(w, http.StatusNotFound, fmt.Errorf("record not found"))
+		return
+	}
+
+	before, err := patchDocument(exist)
+	if err != nil {
+		config.Logger.Error("Ошибка кодирования записи: ", err)
+		responseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	after, err := apply(before, body)
+	if errors.Is(err, patch.ErrInvalid) {
+		config.Logger.Error("Ошибка парсинга патча: ", err)
+		responseFieldError(w, r, "body", err.Error())
+		return
+	}
+	if err != nil {
+		config.Logger.Error("Ошибка применения патча: ", err)
+		responseError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	input, err := patchedFields(before, after)
+	if err == nil {
+		err = validation.Validate(input)
+	}
+	var invalid validation.Errors
+	if errors.As(err, &invalid) {
+		config.Logger.Error("Ошибка проверки входных данных: ", err)
+		responseProblem(w, r, invalid)
+		return
+	}
+	if err != nil {
+		config.Logger.Error("Ошибка применения патча: ", err)
+		responseError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	applyUpdate(&exist, input)
+
+	err = repository.UpdatePerson(exist)
+	if err != nil {
+		config.Logger.Error("Ошибка обновления данных в БД: ", err)
+		responseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	config.Logger.Infof("Успешно обновлена запись с ID %d", id)
+	response(w, http.StatusOK, exist)
+}
+
+// patchDocument возвращает JSON записи, к которому применяется патч. Пустые изменяемые
+// поля присутствуют со значением null, чтобы к ним можно было применить replace и remove.
+func patchDocument(p models.Person) ([]byte, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	for key := range patchableFields {
+		if _, ok := doc[key]; !ok {
+			doc[key] = nil
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// patchedFields сравнивает документ записи до и после патча и возвращает изменённые
+// поля. Удалённые поля получают пустые значения, изменение прочих полей - ошибка.
+func patchedFields(before, after []byte) (models.UpdatePerson, error) {
+	var old, patched map[string]any
+	if err := json.Unmarshal(before, &old); err != nil {
+		return models.UpdatePerson{}, err
+	}
+	if err := json.Unmarshal(after, &patched); err != nil {
+		return models.UpdatePerson{}, fmt.Errorf("patched document must be an object")
+	}
+
+	var invalid validation.Errors
+	changed := map[string]any{}
+	for _, key := range unionKeys(old, patched) {
+		value, ok := patched[key]
+		if reflect.DeepEqual(old[key], value) {
+			continue
+		}
+
+		empty, patchable := patchableFields[key]
+		if !patchable {
+			invalid = append(invalid, validation.FieldError{Name: key, Reason: "is read-only"})
+			continue
+		}
+		if !ok || value == nil {
+			value = empty
+		}
+		changed[key] = value
+	}
+	if len(invalid) > 0 {
+		return models.UpdatePerson{}, invalid
+	}
+
+	data, err := json.Marshal(changed)
+	if err != nil {
+		return models.UpdatePerson{}, err
+	}
+
+	var input models.UpdatePerson
+	err = json.Unmarshal(data, &input)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return models.UpdatePerson{}, validation.Errors{{Name: typeErr.Field, Reason: "must be a " + typeErr.Type.Kind().String()}}
+	}
+	return input, err
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
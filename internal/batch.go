@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"context"
+	"sync"
+)
+
+// MaxBatchSize - максимальное число имён в одном запросе к agify, genderize и nationalize.
+const MaxBatchSize = 10
+
+// BatchEnricher - провайдер, умеющий обрабатывать несколько имён одним запросом.
+// Результаты возвращаются в том же порядке, что и имена.
+type BatchEnricher interface {
+	Enricher
+	EnrichBatch(ctx context.Context, names []string, countryID string) ([]Result, error)
+}
+
+// EnrichBatch обогащает набор записей и возвращает результаты в том же порядке.
+// Имена группируются по коду страны; найденные в кэше не запрашиваются, остальные
+// делятся на группы по MaxBatchSize, и каждый провайдер получает один запрос на группу.
+// Провайдеры без поддержки пакетного режима опрашиваются по одному имени, провайдеры,
+// учитывающие полное имя, - по одной записи. Код страны, двухпроходный режим
+// и WithRefresh работают так же, как в EnrichPerson.
+func EnrichBatch(ctx context.Context, queries []Query) ([]Result, error) {
+	queries = append([]Query(nil), queries...)
+	byCountry := map[string][]string{}
+	for i := range queries {
+		if queries[i].CountryID == "" {
+			queries[i].CountryID = defaultCountry()
+		}
+		byCountry[queries[i].CountryID] = append(byCountry[queries[i].CountryID], queries[i].Name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, enrichTimeout())
+	defer cancel()
+
+	byName := make(map[string]map[string]Result, len(byCountry))
+	for countryID, names := range byCountry {
+		res, err := enrichNames(ctx, names, countryID)
+		if err != nil {
+			return nil, err
+		}
+		byName[countryID] = res
+	}
+
+	results := make([]Result, len(queries))
+	for i, q := range queries {
+		res, err := enrichFullName(ctx, q, byName[q.CountryID][q.Name])
+		if err != nil {
+			return nil, err
+		}
+		results[i] = res
+	}
+	return results, nil
+}
+
+// enrichNames пакетно опрашивает провайдеров, которым достаточно имени, с учётом кэша.
+func enrichNames(ctx context.Context, names []string, countryID string) (map[string]Result, error) {
+	results := make(map[string]Result, len(names))
+
+	var pending []string
+	for _, name := range names {
+		if _, ok := results[name]; ok {
+			continue
+		}
+		if !refresh(ctx) {
+			if res, ok := cacheGet(Query{Name: name, CountryID: countryID}); ok {
+				results[name] = res
+				continue
+			}
+		}
+		results[name] = Result{}
+		pending = append(pending, name)
+	}
+
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	enrichers := Enrichers()
+	partial := make([]map[string]Result, len(enrichers))
+	errs := make([]error, len(enrichers))
+
+	if countryID == "" && twoPass() {
+		runBatch(ctx, enrichers, pending, "", partial, errs, func(e Enricher) bool { return !fullNameAware(e) && !countryAware(e) })
+
+		byCountry := map[string][]string{}
+		for _, name := range pending {
+			var first Result
+			for i := range enrichers {
+				if errs[i] == nil && partial[i] != nil {
+					first.merge(partial[i][name])
+				}
+			}
+			byCountry[first.Nationality] = append(byCountry[first.Nationality], name)
+		}
+
+		for country, group := range byCountry {
+			runBatch(ctx, enrichers, group, country, partial, errs, func(e Enricher) bool { return !fullNameAware(e) && countryAware(e) })
+		}
+	} else {
+		runBatch(ctx, enrichers, pending, countryID, partial, errs, func(e Enricher) bool { return !fullNameAware(e) })
+	}
+
+	var enrichErr EnrichError
+	for i, e := range enrichers {
+		if errs[i] != nil {
+			enrichErr.Errors = append(enrichErr.Errors, ProviderError{Provider: e.Name(), Err: errs[i]})
+		}
+	}
+	if len(enrichErr.Errors) > 0 {
+		return nil, &enrichErr
+	}
+
+	for _, name := range pending {
+		var res Result
+		for i := range enrichers {
+			res.merge(partial[i][name])
+		}
+		results[name] = res
+		cacheSet(Query{Name: name, CountryID: countryID}, res)
+	}
+	return results, nil
+}
+
+// runBatch параллельно запускает отобранных провайдеров для группы имён и дописывает
+// результаты в partial по индексу провайдера.
+func runBatch(ctx context.Context, enrichers []Enricher, names []string, countryID string, partial []map[string]Result, errs []error, include func(Enricher) bool) {
+	var wg sync.WaitGroup
+	for i, e := range enrichers {
+		if !include(e) || errs[i] != nil {
+			continue
+		}
+		if partial[i] == nil {
+			partial[i] = make(map[string]Result, len(names))
+		}
+
+		wg.Add(1)
+		go func(i int, e Enricher) {
+			defer wg.Done()
+			res, err := enrichAll(ctx, e, names, countryID)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for j, name := range names {
+				partial[i][name] = res[j]
+			}
+		}(i, e)
+	}
+	wg.Wait()
+}
+
+func enrichAll(ctx context.Context, e Enricher, names []string, countryID string) ([]Result, error) {
+	results := make([]Result, 0, len(names))
+	batcher, isBatch := e.(BatchEnricher)
+
+	for start := 0; start < len(names); start += MaxBatchSize {
+		chunk := names[start:min(start+MaxBatchSize, len(names))]
+
+		if isBatch {
+			res, err := batcher.EnrichBatch(ctx, chunk, countryID)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, res...)
+			continue
+		}
+
+		for _, name := range chunk {
+			res, err := e.Enrich(ctx, Query{Name: name, CountryID: countryID})
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, res)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeEnricher отвечает возрастом, равным длине имени, и национальностью из nationalities
+// и записывает полученные запросы.
+type fakeEnricher struct {
+	name          string
+	usesCountry   bool
+	nationalities map[string]string
+
+	mu      sync.Mutex
+	batches []fakeBatch
+	singles []Query
+}
+
+type fakeBatch struct {
+	names     []string
+	countryID string
+}
+
+func (f *fakeEnricher) Name() string      { return f.name }
+func (f *fakeEnricher) UsesCountry() bool { return f.usesCountry }
+
+func (f *fakeEnricher) result(name string) Result {
+	return Result{Age: len([]rune(name)), Nationality: f.nationalities[name]}
+}
+
+func (f *fakeEnricher) Enrich(ctx context.Context, q Query) (Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.singles = append(f.singles, q)
+	return f.result(q.Name), nil
+}
+
+// batchEnricher - fakeEnricher с пакетным режимом.
+type batchEnricher struct {
+	*fakeEnricher
+}
+
+func (f batchEnricher) EnrichBatch(ctx context.Context, names []string, countryID string) ([]Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, fakeBatch{names: append([]string(nil), names...), countryID: countryID})
+	results := make([]Result, len(names))
+	for i, name := range names {
+		results[i] = f.result(name)
+	}
+	return results, nil
+}
+
+// useEnrichers подменяет реестр провайдеров и кэш на время теста.
+func useEnrichers(t *testing.T, enrichers ...Enricher) {
+	t.Helper()
+	registryMu.Lock()
+	saved := registry
+	registry = enrichers
+	registryMu.Unlock()
+	savedCache := currentCache()
+	SetCache(nil)
+
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+		SetCache(savedCache)
+	})
+}
+
+// batchesByCountry группирует записанные пакеты по коду страны.
+func batchesByCountry(batches []fakeBatch) map[string][][]string {
+	byCountry := map[string][][]string{}
+	for _, b := range batches {
+		byCountry[b.countryID] = append(byCountry[b.countryID], b.names)
+	}
+	return byCountry
+}
+
+func TestEnrichBatchGroupsNames(t *testing.T) {
+	batch := &fakeEnricher{name: "batch"}
+	single := &fakeEnricher{name: "single"}
+	useEnrichers(t, batchEnricher{batch}, single)
+
+	cache := NewLRUCache(100, 0)
+	SetCache(cache)
+	cacheSet(Query{Name: "Кэш", CountryID: "RU"}, Result{Age: 99})
+
+	var names []string
+	for i := 0; i < 12; i++ {
+		names = append(names, strings.Repeat("я", i+1))
+	}
+	var queries []Query
+	for _, name := range names {
+		queries = append(queries, Query{Name: name, CountryID: "RU"})
+	}
+	queries = append(queries,
+		Query{Name: "я", CountryID: "RU"},   // повтор
+		Query{Name: "Кэш", CountryID: "RU"}, // есть в кэше
+		Query{Name: "я", CountryID: "KZ"},   // то же имя в другой стране
+		Query{Name: "Кэш", CountryID: "KZ"},
+	)
+
+	results, err := EnrichBatch(context.Background(), queries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, q := range queries {
+		want := len([]rune(q.Name))
+		if q.Name == "Кэш" && q.CountryID == "RU" {
+			want = 99
+		}
+		if results[i].Age != want {
+			t.Errorf("result %d (%s, %s): age %d, want %d", i, q.Name, q.CountryID, results[i].Age, want)
+		}
+	}
+
+	want := map[string][][]string{
+		"RU": {names[:MaxBatchSize], names[MaxBatchSize:]},
+		"KZ": {{"я", "Кэш"}},
+	}
+	if got := batchesByCountry(batch.batches); !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %v, want %v", got, want)
+	}
+
+	// Провайдер без пакетного режима опрашивается по одному разу на уникальное имя.
+	if len(single.singles) != len(names)+2 {
+		t.Errorf("single provider got %d requests, want %d", len(single.singles), len(names)+2)
+	}
+
+	// Результаты кэшируются: повторный запрос не обращается к провайдерам.
+	batch.batches = nil
+	if _, err := EnrichBatch(context.Background(), queries); err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.batches) != 0 {
+		t.Errorf("cached names requested again: %v", batch.batches)
+	}
+
+	// WithRefresh обходит кэш.
+	if _, err := EnrichBatch(WithRefresh(context.Background()), queries[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.batches) != 1 {
+		t.Errorf("refresh did not query providers: %v", batch.batches)
+	}
+}
+
+func TestEnrichBatchTwoPass(t *testing.T) {
+	t.Setenv("ENRICH_TWO_PASS", "true")
+	t.Setenv("ENRICH_COUNTRY_ID", "")
+	nationalize := &fakeEnricher{name: "nationalize", nationalities: map[string]string{
+		"Иван": "RU", "Олесь": "UA", "Пётр": "RU", "Zzz": "",
+	}}
+	agify := &fakeEnricher{name: "agify", usesCountry: true}
+	useEnrichers(t, batchEnricher{nationalize}, batchEnricher{agify})
+
+	queries := []Query{{Name: "Иван"}, {Name: "Олесь"}, {Name: "Пётр"}, {Name: "Zzz"}, {Name: "Иван"}}
+	results, err := EnrichBatch(context.Background(), queries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []fakeBatch{{names: []string{"Иван", "Олесь", "Пётр", "Zzz"}}}; !reflect.DeepEqual(nationalize.batches, want) {
+		t.Errorf("first pass = %v, want %v", nationalize.batches, want)
+	}
+	got := batchesByCountry(agify.batches)
+	for _, names := range got {
+		for _, b := range names {
+			sort.Strings(b)
+		}
+	}
+	want := map[string][][]string{"RU": {{"Иван", "Пётр"}}, "UA": {{"Олесь"}}, "": {{"Zzz"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("second pass = %v, want %v", got, want)
+	}
+
+	for i, q := range queries {
+		if results[i].Nationality != nationalize.nationalities[q.Name] || results[i].Age != len([]rune(q.Name)) {
+			t.Errorf("result %d (%s) = %+v", i, q.Name, results[i])
+		}
+	}
+}
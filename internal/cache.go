@@ -0,0 +1,280 @@
+package internal
+
+import (
+	"container/list"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"task/config"
+	"task/models"
+	"time"
+)
+
+// Cache хранит результаты обогащения по имени.
+type Cache interface {
+	Get(name string) (Result, bool)
+	Set(name string, res Result)
+}
+
+// CacheStats - счётчики обращений к кэшу обогащения.
+type CacheStats struct {
+	Enabled bool   `json:"enabled"`
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Size    int    `json:"size"`
+}
+
+var (
+	cacheMu     sync.RWMutex
+	cache       Cache
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
+)
+
+func cacheKey(q Query) string {
+	key := strings.ToLower(strings.TrimSpace(q.Name))
+	if q.CountryID != "" {
+		key += "|" + strings.ToUpper(q.CountryID)
+	}
+	return key
+}
+
+// SetCache устанавливает кэш перед EnrichPerson. nil отключает кэширование.
+func SetCache(c Cache) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cache = c
+}
+
+func currentCache() Cache {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+	return cache
+}
+
+func cacheGet(q Query) (Result, bool) {
+	c := currentCache()
+	if c == nil {
+		return Result{}, false
+	}
+
+	res, ok := c.Get(cacheKey(q))
+	if ok {
+		cacheHits.Add(1)
+	} else {
+		cacheMisses.Add(1)
+	}
+	return res, ok
+}
+
+func cacheSet(q Query, res Result) {
+	if c := currentCache(); c != nil {
+		c.Set(cacheKey(q), res)
+	}
+}
+
+// Stats возвращает текущие значения счётчиков кэша.
+func Stats() CacheStats {
+	stats := CacheStats{
+		Hits:   cacheHits.Load(),
+		Misses: cacheMisses.Load(),
+	}
+
+	c := currentCache()
+	stats.Enabled = c != nil
+	if sized, ok := c.(interface{ Len() int }); ok {
+		stats.Size = sized.Len()
+	}
+	return stats
+}
+
+type lruEntry struct {
+	key       string
+	res       Result
+	expiresAt time.Time
+}
+
+// LRUCache - потокобезопасный кэш в памяти с вытеснением давно неиспользуемых записей и TTL.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func NewLRUCache(capacity int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(name string) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[name]
+	if !ok {
+		return Result{}, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if c.ttl > 0 && time.Now().After(entry.expiresAt) {
+		c.ll.Remove(el)
+		delete(c.items, name)
+		return Result{}, false
+	}
+
+	c.ll.MoveToFront(el)
+	return entry.res, true
+}
+
+func (c *LRUCache) Set(name string, res Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+	if el, ok := c.items[name]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.res = res
+		entry.expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[name] = c.ll.PushFront(&lruEntry{key: name, res: res, expiresAt: expiresAt})
+	if c.capacity > 0 && c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// CacheStore - постоянное хранилище кэша (например, таблица в PostgreSQL).
+type CacheStore interface {
+	GetCachedEnrichment(name string) (models.EnrichmentCache, error)
+	SaveCachedEnrichment(entry models.EnrichmentCache) error
+}
+
+// StoreCache адаптирует CacheStore к интерфейсу Cache.
+type StoreCache struct {
+	store CacheStore
+	ttl   time.Duration
+}
+
+func NewStoreCache(store CacheStore, ttl time.Duration) *StoreCache {
+	return &StoreCache{store: store, ttl: ttl}
+}
+
+func (c *StoreCache) Get(name string) (Result, bool) {
+	entry, err := c.store.GetCachedEnrichment(name)
+	if err != nil {
+		return Result{}, false
+	}
+	if c.ttl > 0 && time.Since(entry.FetchedAt) > c.ttl {
+		return Result{}, false
+	}
+
+	res := Result{Age: entry.Age, Gender: entry.Gender, Nationality: entry.Nationality}
+	if entry.Details != "" {
+		if err := json.Unmarshal([]byte(entry.Details), &res.Details); err != nil {
+			return Result{}, false
+		}
+	}
+	return res, true
+}
+
+func (c *StoreCache) Set(name string, res Result) {
+	details, err := json.Marshal(res.Details)
+	if err != nil {
+		config.Logger.Warn("Ошибка сохранения кэша обогащения: ", err)
+		return
+	}
+
+	err = c.store.SaveCachedEnrichment(models.EnrichmentCache{
+		Name:        name,
+		Age:         res.Age,
+		Gender:      res.Gender,
+		Nationality: res.Nationality,
+		Details:     string(details),
+		FetchedAt:   time.Now(),
+	})
+	if err != nil {
+		config.Logger.Warn("Ошибка сохранения кэша обогащения: ", err)
+	}
+}
+
+// TieredCache опрашивает уровни по порядку и заполняет верхние уровни при попадании в нижние.
+type TieredCache []Cache
+
+func (t TieredCache) Get(name string) (Result, bool) {
+	for i, c := range t {
+		if res, ok := c.Get(name); ok {
+			for _, upper := range t[:i] {
+				upper.Set(name, res)
+			}
+			return res, true
+		}
+	}
+	return Result{}, false
+}
+
+func (t TieredCache) Set(name string, res Result) {
+	for _, c := range t {
+		c.Set(name, res)
+	}
+}
+
+func (t TieredCache) Len() int {
+	if len(t) == 0 {
+		return 0
+	}
+	if sized, ok := t[0].(interface{ Len() int }); ok {
+		return sized.Len()
+	}
+	return 0
+}
+
+// LoadCache настраивает кэш обогащения по переменным окружения:
+// ENRICH_CACHE_SIZE (0 отключает кэш в памяти), ENRICH_CACHE_TTL и ENRICH_CACHE_DB.
+func LoadCache(store CacheStore) {
+	size := config.EnrichCacheSize
+	if v := os.Getenv("ENRICH_CACHE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			size = n
+		}
+	}
+
+	ttl := config.EnrichCacheTTL
+	if v := os.Getenv("ENRICH_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			ttl = d
+		}
+	}
+
+	var tiers TieredCache
+	if size > 0 {
+		tiers = append(tiers, NewLRUCache(size, ttl))
+	}
+	if useDB, _ := strconv.ParseBool(os.Getenv("ENRICH_CACHE_DB")); useDB && store != nil {
+		tiers = append(tiers, NewStoreCache(store, ttl))
+	}
+
+	if len(tiers) == 0 {
+		SetCache(nil)
+		return
+	}
+	SetCache(tiers)
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"task/models"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheEviction(t *testing.T) {
+	c := NewLRUCache(2, 0)
+	c.Set("анна", Result{Age: 1})
+	c.Set("иван", Result{Age: 2})
+	c.Get("анна") // анна становится недавно использованной
+	c.Set("пётр", Result{Age: 3})
+
+	if _, ok := c.Get("иван"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	for name, age := range map[string]int{"анна": 1, "пётр": 3} {
+		if res, ok := c.Get(name); !ok || res.Age != age {
+			t.Errorf("Get(%s) = %+v, %t", name, res, ok)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+
+	c.Set("анна", Result{Age: 10})
+	if res, _ := c.Get("анна"); res.Age != 10 || c.Len() != 2 {
+		t.Errorf("overwrite: %+v, len %d", res, c.Len())
+	}
+}
+
+func TestLRUCacheTTL(t *testing.T) {
+	c := NewLRUCache(10, 20*time.Millisecond)
+	c.Set("иван", Result{Age: 30})
+	if _, ok := c.Get("иван"); !ok {
+		t.Fatal("fresh entry missing")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("иван"); ok {
+		t.Error("expired entry returned")
+	}
+	if c.Len() != 0 {
+		t.Errorf("expired entry not removed, len %d", c.Len())
+	}
+}
+
+// memoryStore - CacheStore в памяти для тестов.
+type memoryStore map[string]models.EnrichmentCache
+
+func (s memoryStore) GetCachedEnrichment(name string) (models.EnrichmentCache, error) {
+	entry, ok := s[name]
+	if !ok {
+		return models.EnrichmentCache{}, errors.New("not found")
+	}
+	return entry, nil
+}
+
+func (s memoryStore) SaveCachedEnrichment(entry models.EnrichmentCache) error {
+	s[entry.Name] = entry
+	return nil
+}
+
+func TestStoreCacheRoundTrip(t *testing.T) {
+	store := memoryStore{}
+	c := NewStoreCache(store, time.Hour)
+	res := Result{Age: 40, Gender: models.Male, Nationality: "RU", Details: []models.PersonEnrichment{
+		{Provider: "agify", Field: models.FieldAge, Value: "40", Count: 120},
+		{Provider: "nationalize", Field: models.FieldNationality, Value: "RU", Probability: 0.6},
+	}}
+
+	c.Set("иван|RU", res)
+	got, ok := c.Get("иван|RU")
+	if !ok || !reflect.DeepEqual(got, res) {
+		t.Errorf("Get = %+v, %t, want %+v", got, ok, res)
+	}
+	if _, ok := c.Get("анна"); ok {
+		t.Error("missing entry found")
+	}
+
+	entry := store["иван|RU"]
+	entry.FetchedAt = time.Now().Add(-2 * time.Hour)
+	store["иван|RU"] = entry
+	if _, ok := c.Get("иван|RU"); ok {
+		t.Error("stale entry returned")
+	}
+
+	store["битая"] = models.EnrichmentCache{Name: "битая", Details: "{", FetchedAt: time.Now()}
+	if _, ok := c.Get("битая"); ok {
+		t.Error("entry with malformed details returned")
+	}
+}
+
+func TestTieredCachePromotion(t *testing.T) {
+	memory := NewLRUCache(10, 0)
+	store := memoryStore{}
+	tiers := TieredCache{memory, NewStoreCache(store, 0)}
+
+	tiers.Set("иван", Result{Age: 30})
+	if _, ok := store["иван"]; !ok || memory.Len() != 1 {
+		t.Fatal("Set did not write every tier")
+	}
+
+	NewStoreCache(store, 0).Set("анна", Result{Age: 25})
+	if res, ok := tiers.Get("анна"); !ok || res.Age != 25 {
+		t.Fatalf("Get from lower tier = %+v, %t", res, ok)
+	}
+	if res, ok := memory.Get("анна"); !ok || res.Age != 25 {
+		t.Error("lower tier hit was not promoted to memory")
+	}
+	if tiers.Len() != 2 {
+		t.Errorf("Len = %d, want size of the memory tier", tiers.Len())
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	defer SetCache(nil)
+	SetCache(NewLRUCache(10, 0))
+	before := Stats()
+
+	q := Query{Name: " Иван ", CountryID: "ru"}
+	if _, ok := cacheGet(q); ok {
+		t.Fatal("empty cache hit")
+	}
+	cacheSet(q, Result{Age: 30})
+	if res, ok := cacheGet(Query{Name: "иван", CountryID: "RU"}); !ok || res.Age != 30 {
+		t.Fatalf("key is not normalized: %+v, %t", res, ok)
+	}
+	if _, ok := cacheGet(Query{Name: "иван"}); ok {
+		t.Error("country hint is not part of the key")
+	}
+
+	stats := Stats()
+	if !stats.Enabled || stats.Size != 1 || stats.Hits-before.Hits != 1 || stats.Misses-before.Misses != 2 {
+		t.Errorf("Stats = %+v, before %+v", stats, before)
+	}
+
+	SetCache(nil)
+	if Stats().Enabled {
+		t.Error("disabled cache reported as enabled")
+	}
+}
@@ -0,0 +1,294 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"task/config"
+	"time"
+)
+
+// ClientConfig - настройки HTTP-клиента провайдера обогащения.
+type ClientConfig struct {
+	Timeout          time.Duration
+	MaxRetries       int
+	Backoff          time.Duration
+	MaxBackoff       time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	QuotaReserve     int
+	QuotaWindow      time.Duration
+	APIKey           string
+}
+
+// LoadClientConfig читает настройки клиента для провайдера. Сначала проверяется
+// переменная с префиксом провайдера (например, AGIFY_HTTP_TIMEOUT), затем общая
+// с префиксом ENRICH_ (ENRICH_HTTP_TIMEOUT).
+func LoadClientConfig(provider string) ClientConfig {
+	return ClientConfig{
+		Timeout:          providerDuration(provider, "HTTP_TIMEOUT", config.EnrichHTTPTimeout),
+		MaxRetries:       providerInt(provider, "MAX_RETRIES", config.EnrichMaxRetries),
+		Backoff:          providerDuration(provider, "RETRY_BACKOFF", config.EnrichRetryBackoff),
+		MaxBackoff:       providerDuration(provider, "RETRY_MAX_BACKOFF", config.EnrichRetryMaxBackoff),
+		BreakerThreshold: providerInt(provider, "BREAKER_THRESHOLD", config.EnrichBreakerThreshold),
+		BreakerCooldown:  providerDuration(provider, "BREAKER_COOLDOWN", config.EnrichBreakerCooldown),
+		QuotaReserve:     providerInt(provider, "QUOTA_RESERVE", config.EnrichQuotaReserve),
+		QuotaWindow:      providerDuration(provider, "QUOTA_WINDOW", config.EnrichQuotaWindow),
+		APIKey:           providerAPIKey(provider),
+	}
+}
+
+// providerAPIKey возвращает ключ API провайдера из переменной окружения
+// (AGIFY_API_KEY, затем ENRICH_API_KEY) или из JSON-файла ENRICH_CREDENTIALS_FILE
+// вида {"agify": "ключ", "genderize": "ключ"}.
+func providerAPIKey(provider string) string {
+	if key := providerEnv(provider, "API_KEY"); key != "" {
+		return key
+	}
+
+	path := os.Getenv("ENRICH_CREDENTIALS_FILE")
+	if path == "" {
+		return ""
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		config.Logger.Warn("Не удалось прочитать файл ключей провайдеров: ", err)
+		return ""
+	}
+
+	var keys map[string]string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		config.Logger.Warn("Ошибка разбора файла ключей провайдеров: ", err)
+		return ""
+	}
+	return keys[provider]
+}
+
+func providerEnv(provider, key string) string {
+	return envOr(strings.ToUpper(provider)+"_"+key, os.Getenv("ENRICH_"+key))
+}
+
+func providerDuration(provider, key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(providerEnv(provider, key)); err == nil && d >= 0 {
+		return d
+	}
+	return fallback
+}
+
+func providerInt(provider, key string, fallback int) int {
+	if n, err := strconv.Atoi(providerEnv(provider, key)); err == nil && n >= 0 {
+		return n
+	}
+	return fallback
+}
+
+// StatusError - ответ провайдера с кодом, отличным от 200.
+type StatusError struct {
+	Code       int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return "неожиданный статус ответа: " + e.Status
+}
+
+func (e *StatusError) retryable() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= http.StatusInternalServerError
+}
+
+// Client выполняет запросы к провайдеру с таймаутом, повторами с экспоненциальной
+// задержкой и джиттером, учётом Retry-After, автоматическим выключателем и
+// локальным ограничением по квоте провайдера.
+type Client struct {
+	provider string
+	cfg      ClientConfig
+	http     *http.Client
+	breaker  *Breaker
+	quota    *Quota
+}
+
+var (
+	clientsMu sync.RWMutex
+	clients   = map[string]*Client{}
+)
+
+// NewClient создаёт клиент и регистрирует его для BreakerStates и QuotaStates.
+func NewClient(provider string, cfg ClientConfig) *Client {
+	c := &Client{
+		provider: provider,
+		cfg:      cfg,
+		http:     &http.Client{Timeout: cfg.Timeout},
+		breaker:  NewBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		quota:    NewQuota(cfg.QuotaReserve, cfg.QuotaWindow),
+	}
+
+	clientsMu.Lock()
+	clients[provider] = c
+	clientsMu.Unlock()
+
+	return c
+}
+
+func (c *Client) GetJSON(ctx context.Context, rawURL string, dst any) error {
+	for attempt := 0; ; attempt++ {
+		if err := c.quota.Allow(c.provider); err != nil {
+			return err
+		}
+		if err := c.breaker.Allow(); err != nil {
+			return err
+		}
+
+		err := c.getJSON(ctx, rawURL, dst)
+		if err == nil {
+			c.breaker.Success()
+			return nil
+		}
+
+		var statusErr *StatusError
+		isStatus := errors.As(err, &statusErr)
+		switch {
+		case ctx.Err() != nil:
+			c.breaker.Release()
+			return err
+		case isStatus && statusErr.Code == http.StatusTooManyRequests:
+			c.breaker.Release()
+		case isStatus && !statusErr.retryable():
+			c.breaker.Success()
+			return err
+		default:
+			c.breaker.Failure()
+		}
+
+		if attempt >= c.cfg.MaxRetries {
+			return err
+		}
+
+		delay := c.backoff(attempt)
+		if isStatus && statusErr.RetryAfter > delay {
+			if c.cfg.MaxBackoff > 0 && statusErr.RetryAfter > c.cfg.MaxBackoff {
+				return &QuotaError{Provider: c.provider, ResetAt: time.Now().Add(statusErr.RetryAfter)}
+			}
+			delay = statusErr.RetryAfter
+		}
+
+		config.Logger.Debugf("Повтор запроса к %s через %s (попытка %d): %v", c.provider, delay, attempt+1, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *Client) getJSON(ctx context.Context, rawURL string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+
+	if c.cfg.APIKey != "" {
+		q := req.URL.Query()
+		q.Set("apikey", c.cfg.APIKey)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(urlErr.URL)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	c.quota.Update(resp)
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{
+			Code:       resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("ошибка декодирования ответа: %w", err)
+	}
+	return nil
+}
+
+// backoff возвращает задержку перед повтором: экспоненциальный рост с равномерным джиттером.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.cfg.Backoff << attempt
+	if d <= 0 || (c.cfg.MaxBackoff > 0 && d > c.cfg.MaxBackoff) {
+		d = c.cfg.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// redactURL скрывает ключ API в адресе запроса перед записью в лог или ошибку.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	q := u.Query()
+	if q.Has("apikey") {
+		q.Set("apikey", "REDACTED")
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// BreakerStatus - состояние выключателя провайдера для мониторинга.
+type BreakerStatus struct {
+	Provider string       `json:"provider"`
+	State    BreakerState `json:"state"`
+	Failures int          `json:"failures"`
+	OpenedAt *time.Time   `json:"opened_at,omitempty"`
+}
+
+// BreakerStates возвращает состояние выключателей всех провайдеров.
+func BreakerStates() []BreakerStatus {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+
+	states := make([]BreakerStatus, 0, len(clients))
+	for provider, c := range clients {
+		status := c.breaker.Status()
+		status.Provider = provider
+		states = append(states, status)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Provider < states[j].Provider
+	})
+	return states
+}
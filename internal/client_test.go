@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"task/config"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	config.LoadLoger()
+	os.Exit(m.Run())
+}
+
+// reply - ответ тестового сервера: код и заголовки.
+type reply struct {
+	code    int
+	headers map[string]string
+}
+
+// stubServer отвечает по очереди ответами replies (последний повторяется) и считает запросы.
+func stubServer(t *testing.T, replies ...reply) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		rep := replies[min(n, len(replies)-1)]
+		for k, v := range rep.headers {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(rep.code)
+		if rep.code == http.StatusOK {
+			w.Write([]byte(`{"age":30}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func testClient(provider string, maxRetries, threshold int) *Client {
+	return NewClient(provider, ClientConfig{
+		Timeout:          time.Second,
+		MaxRetries:       maxRetries,
+		Backoff:          time.Millisecond,
+		MaxBackoff:       10 * time.Millisecond,
+		BreakerThreshold: threshold,
+		BreakerCooldown:  time.Hour,
+		QuotaWindow:      time.Minute,
+	})
+}
+
+func TestClientGetJSON(t *testing.T) {
+	ok := reply{code: http.StatusOK}
+	tests := []struct {
+		name     string
+		replies  []reply
+		wantCode int // 0 - успех
+		quota    bool
+		calls    int32
+		failures int
+	}{
+		{"success", []reply{ok}, 0, false, 1, 0},
+		{"retry after 500", []reply{{code: 500}, {code: 503}, ok}, 0, false, 3, 0},
+		{"retries exhausted", []reply{{code: 500}}, 500, false, 3, 3},
+		{"client error is not retried", []reply{{code: 404}}, 404, false, 1, 0},
+		{"long Retry-After returns quota error", []reply{{code: 429, headers: map[string]string{"Retry-After": "60"}}}, 0, true, 1, 0},
+		{"429 without headers blocks for quota window", []reply{{code: 429}}, 0, true, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := stubServer(t, tt.replies...)
+			c := testClient("test", 2, 10)
+
+			var dst struct{ Age int }
+			err := c.GetJSON(context.Background(), srv.URL, &dst)
+
+			var statusErr *StatusError
+			var quotaErr *QuotaError
+			switch {
+			case tt.quota:
+				if !errors.As(err, &quotaErr) || time.Until(quotaErr.ResetAt) < 50*time.Second {
+					t.Errorf("err = %v, want quota error for a minute", err)
+				}
+			case tt.wantCode != 0:
+				if !errors.As(err, &statusErr) || statusErr.Code != tt.wantCode {
+					t.Errorf("err = %v, want status %d", err, tt.wantCode)
+				}
+			case err != nil || dst.Age != 30:
+				t.Errorf("err = %v, age = %d", err, dst.Age)
+			}
+			if calls.Load() != tt.calls {
+				t.Errorf("calls = %d, want %d", calls.Load(), tt.calls)
+			}
+			if status := c.breaker.Status(); status.Failures != tt.failures || status.State != BreakerClosed {
+				t.Errorf("breaker = %+v, want closed with %d failures", status, tt.failures)
+			}
+		})
+	}
+}
+
+func TestClientBreakerTransitions(t *testing.T) {
+	srv, calls := stubServer(t, reply{code: 500}, reply{code: 500}, reply{code: 429}, reply{code: http.StatusOK})
+	c := testClient("test", 0, 2)
+	ctx := context.Background()
+	var dst struct{ Age int }
+
+	c.GetJSON(ctx, srv.URL, &dst)
+	if state := c.breaker.Status().State; state != BreakerClosed {
+		t.Fatalf("state after one failure = %s", state)
+	}
+	c.GetJSON(ctx, srv.URL, &dst)
+	if state := c.breaker.Status().State; state != BreakerOpen {
+		t.Fatalf("state after threshold = %s", state)
+	}
+	if err := c.GetJSON(ctx, srv.URL, &dst); !errors.Is(err, ErrBreakerOpen) || calls.Load() != 2 {
+		t.Fatalf("open breaker: err = %v, calls = %d", err, calls.Load())
+	}
+
+	// Пробный запрос после cooldown, отменённый вызывающим, не решает судьбу выключателя.
+	c.breaker.cooldown = 0
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := c.GetJSON(cancelled, srv.URL, &dst); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled probe: %v", err)
+	}
+	if state := c.breaker.Status().State; state != BreakerHalfOpen {
+		t.Fatalf("state after cancelled probe = %s", state)
+	}
+
+	// Ответ 429 тоже освобождает пробу: следующий запрос пропускается.
+	if err := c.GetJSON(ctx, srv.URL, &dst); err == nil || c.breaker.Status().State != BreakerHalfOpen {
+		t.Fatalf("429 probe: err = %v, breaker = %+v", err, c.breaker.Status())
+	}
+	c.quota = NewQuota(0, time.Minute) // квота после 429 проверяется в TestQuotaUpdate
+	if err := c.GetJSON(ctx, srv.URL, &dst); err != nil {
+		t.Fatalf("successful probe: %v", err)
+	}
+	if status := c.breaker.Status(); status.State != BreakerClosed || status.Failures != 0 {
+		t.Errorf("breaker after successful probe = %+v", status)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("calls = %d, want 4", calls.Load())
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	b := NewBreaker(1, 10*time.Millisecond)
+	b.Failure()
+	if err := b.Allow(); !errors.Is(err, ErrBreakerOpen) {
+		t.Fatalf("open breaker allowed a request: %v", err)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe after cooldown: %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrBreakerOpen) {
+		t.Error("second request allowed while probing")
+	}
+	b.Release()
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe after release: %v", err)
+	}
+
+	b.Failure()
+	if state := b.Status().State; state != BreakerOpen {
+		t.Errorf("failed probe: state %s, want open", state)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("5"); d != 5*time.Second {
+		t.Errorf("seconds: %s", d)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d := parseRetryAfter(date); d < 50*time.Second || d > time.Minute {
+		t.Errorf("date: %s", d)
+	}
+	for _, v := range []string{"", "-1", "soon"} {
+		if d := parseRetryAfter(v); d != 0 {
+			t.Errorf("%q: %s", v, d)
+		}
+	}
+}
@@ -1,66 +1,195 @@
 package internal
 
 import (
-	"encoding/json"
-	"net/http"
+	"context"
 	"os"
-	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"task/config"
 	"task/models"
+	"time"
 )
 
-func EnrichPerson(name string) (int, models.Gender, string, error) {
+// ProviderError описывает сбой одного внешнего API.
+type ProviderError struct {
+	Provider string
+	Err      error
+}
 
-	agifyURL := os.Getenv("AGIFY_URL") + "/?name=" + name
-	resp, err := http.Get(agifyURL)
-	if err != nil {
-		return 0, 0, "", err
+func (e ProviderError) Error() string {
+	return e.Provider + ": " + e.Err.Error()
+}
+
+func (e ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// EnrichError собирает ошибки всех провайдеров, не вернувших данные.
+type EnrichError struct {
+	Errors []ProviderError
+}
+
+func (e *EnrichError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, pe := range e.Errors {
+		msgs = append(msgs, pe.Error())
+	}
+	return "ошибка обогащения: " + strings.Join(msgs, "; ")
+}
+
+func (e *EnrichError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, pe := range e.Errors {
+		errs = append(errs, pe)
 	}
-	defer resp.Body.Close()
+	return errs
+}
 
-	var ageData models.PersonWihtAge
-	if err := json.NewDecoder(resp.Body).Decode(&ageData); err != nil {
-		return 0, 0, "", err
+func enrichTimeout() time.Duration {
+	if v := os.Getenv("ENRICH_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
 	}
+	return config.EnrichTimeout
+}
+
+func defaultCountry() string {
+	return strings.ToUpper(os.Getenv("ENRICH_COUNTRY_ID"))
+}
 
-	genderizeURL := os.Getenv("GENDERIZE_URL") + "/?name=" + name
-	resp, err = http.Get(genderizeURL)
+func twoPass() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("ENRICH_TWO_PASS"))
+	return enabled
+}
+
+// countryAware сообщает, учитывает ли провайдер код страны из Query.
+func countryAware(e Enricher) bool {
+	ca, ok := e.(CountryAware)
+	return ok && ca.UsesCountry()
+}
+
+// fullNameAware сообщает, использует ли провайдер фамилию и отчество из Query.
+func fullNameAware(e Enricher) bool {
+	fa, ok := e.(FullNameAware)
+	return ok && fa.UsesFullName()
+}
+
+// EnrichPerson параллельно опрашивает всех зарегистрированных провайдеров с общим
+// контекстом и дедлайном. Если хотя бы один провайдер не ответил, возвращается *EnrichError.
+// Без кода страны в запросе используется ENRICH_COUNTRY_ID. Если код страны не задан
+// и включён ENRICH_TWO_PASS, сначала определяется национальность, а затем она передаётся
+// провайдерам, учитывающим страну.
+// Успешные результаты провайдеров, работающих только с именем, кэшируются по имени
+// и стране, см. SetCache и WithRefresh. Провайдеры, учитывающие полное имя, опрашиваются
+// всегда, после чего пол и национальность выбираются взвешенным голосованием.
+func EnrichPerson(ctx context.Context, q Query) (Result, error) {
+	if q.CountryID == "" {
+		q.CountryID = defaultCountry()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, enrichTimeout())
+	defer cancel()
+
+	res, err := enrichName(ctx, q)
 	if err != nil {
-		return 0, 0, "", err
+		return Result{}, err
+	}
+	return enrichFullName(ctx, q, res)
+}
+
+// enrichName опрашивает провайдеров, которым достаточно имени, с учётом кэша.
+func enrichName(ctx context.Context, q Query) (Result, error) {
+	key := Query{Name: q.Name, CountryID: q.CountryID}
+	if !refresh(ctx) {
+		if res, ok := cacheGet(key); ok {
+			return res, nil
+		}
 	}
-	defer resp.Body.Close()
 
-	var genderData models.PersonWihtGender
-	if err := json.NewDecoder(resp.Body).Decode(&genderData); err != nil {
-		return 0, 0, "", err
+	enrichers := Enrichers()
+	results := make([]Result, len(enrichers))
+	errs := make([]error, len(enrichers))
+
+	if q.CountryID == "" && twoPass() {
+		runEnrichers(ctx, enrichers, key, results, errs, func(e Enricher) bool { return !fullNameAware(e) && !countryAware(e) })
+
+		var first Result
+		for i := range enrichers {
+			if errs[i] == nil {
+				first.merge(results[i])
+			}
+		}
+
+		second := key
+		second.CountryID = first.Nationality
+		runEnrichers(ctx, enrichers, second, results, errs, func(e Enricher) bool { return !fullNameAware(e) && countryAware(e) })
+	} else {
+		runEnrichers(ctx, enrichers, key, results, errs, func(e Enricher) bool { return !fullNameAware(e) })
 	}
 
-	nationalizeURL := os.Getenv("NATIONALIZE_URL") + "/?name=" + name
-	resp, err = http.Get(nationalizeURL)
+	res, err := mergeResults(enrichers, results, errs)
 	if err != nil {
-		return 0, 0, "", err
+		return Result{}, err
 	}
-	defer resp.Body.Close()
 
-	var natData models.PersonWihtNationality
-	if err := json.NewDecoder(resp.Body).Decode(&natData); err != nil {
-		return 0, 0, "", err
+	cacheSet(key, res)
+	return res, nil
+}
+
+// enrichFullName дополняет результат по имени ответами провайдеров, учитывающих
+// фамилию и отчество, и выбирает итоговые значения голосованием. base не изменяется.
+func enrichFullName(ctx context.Context, q Query, base Result) (Result, error) {
+	enrichers := Enrichers()
+	results := make([]Result, len(enrichers))
+	errs := make([]error, len(enrichers))
+
+	runEnrichers(ctx, enrichers, q, results, errs, fullNameAware)
+
+	extra, err := mergeResults(enrichers, results, errs)
+	if err != nil {
+		return Result{}, err
 	}
 
-	nationality := ""
-	if len(natData.Nationality) > 0 {
-		sort.Slice(natData.Nationality, func(i, j int) bool {
-			return natData.Nationality[i].Probability > natData.Nationality[j].Probability
-		})
-		nationality = natData.Nationality[0].CountryID
+	res := base
+	res.Details = append([]models.PersonEnrichment(nil), base.Details...)
+	res.merge(extra)
+	res.vote()
+	return res, nil
+}
+
+// mergeResults объединяет ответы провайдеров в порядке реестра или собирает их ошибки.
+func mergeResults(enrichers []Enricher, results []Result, errs []error) (Result, error) {
+	var enrichErr EnrichError
+	var res Result
+	for i, e := range enrichers {
+		if errs[i] != nil {
+			enrichErr.Errors = append(enrichErr.Errors, ProviderError{Provider: e.Name(), Err: errs[i]})
+			continue
+		}
+		res.merge(results[i])
 	}
 
-	gender := models.Unknown
-	switch genderData.Gender {
-	case "male":
-		gender = models.Male
-	case "female":
-		gender = models.Female
+	if len(enrichErr.Errors) > 0 {
+		return Result{}, &enrichErr
 	}
+	return res, nil
+}
 
-	return ageData.Age, gender, nationality, nil
+// runEnrichers параллельно запускает отобранных провайдеров и записывает результаты
+// по их индексам, сохраняя порядок реестра для последующего объединения.
+func runEnrichers(ctx context.Context, enrichers []Enricher, q Query, results []Result, errs []error, include func(Enricher) bool) {
+	var wg sync.WaitGroup
+	for i, e := range enrichers {
+		if !include(e) {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, e Enricher) {
+			defer wg.Done()
+			results[i], errs[i] = e.Enrich(ctx, q)
+		}(i, e)
+	}
+	wg.Wait()
 }
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestEnrichPersonTwoPass(t *testing.T) {
+	t.Setenv("ENRICH_COUNTRY_ID", "")
+	tests := []struct {
+		name        string
+		twoPass     string
+		countryID   string
+		agifyGot    string // код страны, с которым опрошен agify
+		nationality string
+	}{
+		{"two pass uses detected nationality", "true", "", "RU", "RU"},
+		{"explicit country skips first pass", "true", "KZ", "KZ", "RU"},
+		{"single pass without country", "false", "", "", "RU"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENRICH_TWO_PASS", tt.twoPass)
+			nationalize := &fakeEnricher{name: "nationalize", nationalities: map[string]string{"Иван": "RU"}}
+			agify := &fakeEnricher{name: "agify", usesCountry: true}
+			useEnrichers(t, nationalize, agify)
+			SetCache(NewLRUCache(10, 0))
+
+			q := Query{Name: "Иван", Surname: "Петров", CountryID: tt.countryID}
+			res, err := EnrichPerson(context.Background(), q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Age != 4 || res.Nationality != tt.nationality {
+				t.Errorf("result = %+v", res)
+			}
+			want := []Query{{Name: "Иван", CountryID: tt.agifyGot}}
+			if !reflect.DeepEqual(agify.singles, want) {
+				t.Errorf("agify got %v, want %v", agify.singles, want)
+			}
+
+			// Результат кэшируется по имени и исходному коду страны, а не найденному на первом проходе.
+			if _, err := EnrichPerson(context.Background(), q); err != nil {
+				t.Fatal(err)
+			}
+			if len(nationalize.singles) != 1 || len(agify.singles) != 1 {
+				t.Errorf("cached result was requested again: %v, %v", nationalize.singles, agify.singles)
+			}
+			if _, ok := cacheGet(Query{Name: "Иван", CountryID: tt.countryID}); !ok {
+				t.Error("result is not cached under the original country")
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"task/config"
+	"task/models"
+)
+
+// Result содержит данные, полученные от провайдеров обогащения.
+// Нулевое значение поля означает, что провайдер его не заполнял.
+type Result struct {
+	Age         int
+	Gender      models.Gender
+	Nationality string
+
+	// Details - исходные значения провайдеров с вероятностями и размером выборки.
+	Details []models.PersonEnrichment
+}
+
+// Apply переносит результат обогащения в запись о человеке. Источником каждого
+// поля считается провайдер, ответ которого совпал с итоговым значением.
+func (r Result) Apply(p *models.Person) {
+	p.Age = r.Age
+	p.Gender = r.Gender
+	p.Nationality = r.Nationality
+
+	for _, field := range models.InferredFields {
+		*p.FieldProvenance(field) = models.NewProvenance(r.source(field, p.FieldValue(field)))
+	}
+
+	p.Enrichment = make([]models.PersonEnrichment, len(r.Details))
+	for i, d := range r.Details {
+		d.Id = 0
+		d.PersonId = p.Id
+		p.Enrichment[i] = d
+	}
+}
+
+func (r Result) source(field, value string) string {
+	for _, d := range r.Details {
+		if d.Field == field && d.Value == value {
+			return d.Provider
+		}
+	}
+	return ""
+}
+
+// merge дополняет r полями из other, которые ещё не заполнены.
+func (r *Result) merge(other Result) {
+	if r.Age == 0 {
+		r.Age = other.Age
+	}
+	if r.Gender == models.Unknown {
+		r.Gender = other.Gender
+	}
+	if r.Nationality == "" {
+		r.Nationality = other.Nationality
+	}
+	r.Details = append(r.Details, other.Details...)
+}
+
+// vote выбирает пол и национальность по сумме вероятностей из Details, умноженных
+// на вес провайдера (<PROVIDER>_WEIGHT или ENRICH_WEIGHT). При равенстве побеждает
+// значение, встретившееся раньше, то есть провайдер, зарегистрированный первым.
+func (r *Result) vote() {
+	if gender, ok := weightedChoice(r.Details, models.FieldGender); ok {
+		switch gender {
+		case models.Male.String():
+			r.Gender = models.Male
+		case models.Female.String():
+			r.Gender = models.Female
+		}
+	}
+	if nationality, ok := weightedChoice(r.Details, models.FieldNationality); ok {
+		r.Nationality = nationality
+	}
+}
+
+func weightedChoice(details []models.PersonEnrichment, field string) (string, bool) {
+	scores := map[string]float64{}
+	var order []string
+	for _, d := range details {
+		if d.Field != field || d.Value == "" || d.Value == models.Unknown.String() {
+			continue
+		}
+		if _, ok := scores[d.Value]; !ok {
+			order = append(order, d.Value)
+		}
+		scores[d.Value] += d.Probability * providerWeight(d.Provider)
+	}
+
+	var best string
+	for _, value := range order {
+		if scores[value] > scores[best] {
+			best = value
+		}
+	}
+	return best, best != ""
+}
+
+func providerWeight(provider string) float64 {
+	if w, err := strconv.ParseFloat(providerEnv(provider, "WEIGHT"), 64); err == nil && w >= 0 {
+		return w
+	}
+	return config.EnrichWeight
+}
+
+// Query - входные данные для обогащения.
+type Query struct {
+	Name       string
+	Surname    string
+	Patronymic string
+	// CountryID - код страны (ISO 3166-1 alpha-2), уточняющий оценку возраста и пола.
+	CountryID string
+}
+
+// QueryFor собирает запрос на обогащение из записи о человеке.
+func QueryFor(p models.Person, countryID string) Query {
+	return Query{Name: p.Name, Surname: p.Surname, Patronymic: p.Patronymic, CountryID: countryID}
+}
+
+// Enricher - источник данных для обогащения (возраст, пол, национальность) по имени.
+type Enricher interface {
+	Name() string
+	Enrich(ctx context.Context, q Query) (Result, error)
+}
+
+// CountryAware реализуют провайдеры, результат которых зависит от Query.CountryID.
+type CountryAware interface {
+	UsesCountry() bool
+}
+
+// FullNameAware реализуют провайдеры, использующие Query.Surname и Query.Patronymic.
+// Их результаты не кэшируются, поскольку зависят не только от имени.
+type FullNameAware interface {
+	UsesFullName() bool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Enricher
+)
+
+// Register добавляет провайдера в реестр. Провайдер с тем же именем заменяется.
+// Порядок регистрации задаёт приоритет при объединении результатов.
+func Register(e Enricher) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for i, existing := range registry {
+		if existing.Name() == e.Name() {
+			registry[i] = e
+			return
+		}
+	}
+	registry = append(registry, e)
+}
+
+// Unregister удаляет провайдера из реестра по имени.
+func Unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for i, existing := range registry {
+		if existing.Name() == name {
+			registry = append(registry[:i], registry[i+1:]...)
+			return
+		}
+	}
+}
+
+// Enrichers возвращает копию списка зарегистрированных провайдеров.
+func Enrichers() []Enricher {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]Enricher(nil), registry...)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// factories создают провайдеров, доступных для выбора через ENRICHERS.
+var factories = map[string]func() (Enricher, error){
+	"agify": func() (Enricher, error) {
+		return NewAgify(envOr("AGIFY_URL", config.AgifyURL)), nil
+	},
+	"genderize": func() (Enricher, error) {
+		return NewGenderize(envOr("GENDERIZE_URL", config.GenderizeURL)), nil
+	},
+	"nationalize": func() (Enricher, error) {
+		return NewNationalize(envOr("NATIONALIZE_URL", config.NationalizeURL)), nil
+	},
+	"offline": func() (Enricher, error) {
+		return LoadOffline(os.Getenv("OFFLINE_DATASET"))
+	},
+	"rules": func() (Enricher, error) {
+		return NewRules(), nil
+	},
+}
+
+// LoadEnrichers регистрирует провайдеров, перечисленных через запятую в ENRICHERS,
+// в указанном порядке. По умолчанию - agify, genderize и nationalize.
+func LoadEnrichers() {
+	for _, name := range strings.Split(envOr("ENRICHERS", config.Enrichers), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		factory, ok := factories[name]
+		if !ok {
+			config.Logger.Fatal("Неизвестный провайдер обогащения: ", name)
+		}
+
+		e, err := factory()
+		if err != nil {
+			config.Logger.Fatalf("Ошибка создания провайдера обогащения %s: %v", name, err)
+		}
+		Register(e)
+	}
+}
@@ -0,0 +1,225 @@
+// Package mockenrich имитирует API agify.io, genderize.io и nationalize.io для
+// локальной разработки и тестов без доступа к сети.
+package mockenrich
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"task/internal"
+	"time"
+)
+
+// Config - настройки имитатора.
+type Config struct {
+	// Fixtures - заранее заданные ответы по именам. Для остальных имён ответ
+	// детерминированно генерируется по хешу имени.
+	Fixtures []internal.NameStats
+	// Latency - задержка перед каждым ответом.
+	Latency time.Duration
+	// ErrorRate - доля запросов (от 0 до 1), на которые возвращается 500.
+	ErrorRate float64
+	// RateLimit - число имён, разрешённых каждому провайдеру за RateWindow (0 - без ограничений).
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+type quota struct {
+	used        int
+	windowStart time.Time
+}
+
+// Server обслуживает пути /agify/, /genderize/ и /nationalize/. Адреса провайдеров
+// настраиваются как http://host:port/agify и т. д.
+type Server struct {
+	cfg      Config
+	fixtures map[string]internal.NameStats
+	mux      *http.ServeMux
+
+	mu     sync.Mutex
+	quotas map[string]*quota
+}
+
+func New(cfg Config) *Server {
+	s := &Server{
+		cfg:      cfg,
+		fixtures: make(map[string]internal.NameStats, len(cfg.Fixtures)),
+		mux:      http.NewServeMux(),
+		quotas:   map[string]*quota{},
+	}
+	for _, f := range cfg.Fixtures {
+		s.fixtures[strings.ToLower(strings.TrimSpace(f.Name))] = f
+	}
+
+	s.mux.Handle("/agify/", s.provider("agify", s.agify))
+	s.mux.Handle("/genderize/", s.provider("genderize", s.genderize))
+	s.mux.Handle("/nationalize/", s.provider("nationalize", s.nationalize))
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) provider(name string, answer func(name, countryID string) map[string]any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.cfg.Latency > 0 {
+			select {
+			case <-time.After(s.cfg.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		query := r.URL.Query()
+		names, batch := query["name[]"]
+		if !batch {
+			names = query["name"]
+		}
+		if len(names) == 0 || len(names) > internal.MaxBatchSize {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Invalid 'name' parameter"})
+			return
+		}
+
+		if !s.allow(w, name, len(names)) {
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "Request limit reached"})
+			return
+		}
+
+		if s.cfg.ErrorRate > 0 && rand.Float64() < s.cfg.ErrorRate {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+			return
+		}
+
+		countryID := query.Get("country_id")
+		answers := make([]map[string]any, len(names))
+		for i, n := range names {
+			answers[i] = answer(n, countryID)
+		}
+
+		if batch {
+			writeJSON(w, http.StatusOK, answers)
+			return
+		}
+		writeJSON(w, http.StatusOK, answers[0])
+	})
+}
+
+// allow учитывает запрос в квоте провайдера и выставляет заголовки X-Rate-Limit-*.
+func (s *Server) allow(w http.ResponseWriter, provider string, names int) bool {
+	if s.cfg.RateLimit <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.quotas[provider]
+	now := time.Now()
+	if !ok || now.Sub(q.windowStart) >= s.cfg.RateWindow {
+		q = &quota{windowStart: now}
+		s.quotas[provider] = q
+	}
+
+	reset := int(time.Until(q.windowStart.Add(s.cfg.RateWindow)).Seconds())
+	allowed := q.used+names <= s.cfg.RateLimit
+	if allowed {
+		q.used += names
+	}
+
+	h := w.Header()
+	h.Set("X-Rate-Limit-Limit", strconv.Itoa(s.cfg.RateLimit))
+	h.Set("X-Rate-Limit-Remaining", strconv.Itoa(s.cfg.RateLimit-q.used))
+	h.Set("X-Rate-Limit-Reset", strconv.Itoa(reset))
+	if !allowed {
+		h.Set("Retry-After", strconv.Itoa(reset))
+	}
+	return allowed
+}
+
+func (s *Server) stats(name string) internal.NameStats {
+	if f, ok := s.fixtures[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return f
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(name)))
+	sum := h.Sum64()
+
+	male := float64(sum%100) / 100
+	countries := []string{"RU", "UA", "BY", "KZ", "US", "DE"}
+	top := countries[sum%uint64(len(countries))]
+	second := countries[(sum/7)%uint64(len(countries))]
+
+	stats := internal.NameStats{
+		Name:      name,
+		Ages:      map[int]int{18 + int(sum%62): 100 + int(sum%900)},
+		Male:      &male,
+		Countries: map[string]float64{top: 0.6},
+	}
+	if second != top {
+		stats.Countries[second] = 0.25
+	}
+	return stats
+}
+
+func withCountry(resp map[string]any, countryID string) map[string]any {
+	if countryID != "" {
+		resp["country_id"] = countryID
+	}
+	return resp
+}
+
+func (s *Server) agify(name, countryID string) map[string]any {
+	stats := s.stats(name)
+
+	total, sum := 0, 0
+	for age, count := range stats.Ages {
+		total += count
+		sum += age * count
+	}
+
+	var age any
+	if total > 0 {
+		age = (sum + total/2) / total
+	}
+	return withCountry(map[string]any{"name": name, "age": age, "count": total}, countryID)
+}
+
+func (s *Server) genderize(name, countryID string) map[string]any {
+	stats := s.stats(name)
+
+	var gender any
+	probability := 0.0
+	if stats.Male != nil {
+		gender, probability = "male", *stats.Male
+		if probability < 0.5 {
+			gender, probability = "female", 1-probability
+		}
+	}
+	return withCountry(map[string]any{"name": name, "gender": gender, "probability": probability, "count": 1000}, countryID)
+}
+
+func (s *Server) nationalize(name, _ string) map[string]any {
+	stats := s.stats(name)
+
+	countries := make([]map[string]any, 0, len(stats.Countries))
+	for c, p := range stats.Countries {
+		countries = append(countries, map[string]any{"country_id": c, "probability": p})
+	}
+	sort.Slice(countries, func(i, j int) bool {
+		return countries[i]["probability"].(float64) > countries[j]["probability"].(float64)
+	})
+	return map[string]any{"name": name, "count": 1000, "country": countries}
+}
+
+func writeJSON(w http.ResponseWriter, code int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(data)
+}
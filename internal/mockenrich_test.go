@@ -0,0 +1,124 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"task/internal"
+	"task/internal/mockenrich"
+	"task/models"
+	"testing"
+	"time"
+)
+
+var ivan = func() internal.NameStats {
+	male := 0.98
+	return internal.NameStats{Name: "Ivan", Ages: map[int]int{30: 120, 40: 80}, Male: &male, Countries: map[string]float64{"RU": 0.6, "UA": 0.3}}
+}()
+
+func mockServer(t *testing.T, cfg mockenrich.Config) *httptest.Server {
+	t.Helper()
+	cfg.Fixtures = append(cfg.Fixtures, ivan)
+	srv := httptest.NewServer(mockenrich.New(cfg))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMockProviders(t *testing.T) {
+	srv := mockServer(t, mockenrich.Config{})
+	ctx := context.Background()
+
+	// Кириллическое имя транслитерируется и находит фикстуру Ivan.
+	q := internal.Query{Name: "Иван", CountryID: "RU"}
+	age, err := internal.NewAgify(srv.URL+"/agify").Enrich(ctx, q)
+	if err != nil || age.Age != 34 || age.Details[0].Count != 200 {
+		t.Errorf("agify = %+v, %v", age, err)
+	}
+	gender, err := internal.NewGenderize(srv.URL+"/genderize").Enrich(ctx, q)
+	if err != nil || gender.Gender != models.Male || gender.Details[0].Probability != 0.98 {
+		t.Errorf("genderize = %+v, %v", gender, err)
+	}
+	nationality, err := internal.NewNationalize(srv.URL+"/nationalize").Enrich(ctx, q)
+	if err != nil || nationality.Nationality != "RU" || len(nationality.Details) != 2 || nationality.Details[1].Value != "UA" {
+		t.Errorf("nationalize = %+v, %v", nationality, err)
+	}
+}
+
+func TestMockProvidersBatch(t *testing.T) {
+	srv := mockServer(t, mockenrich.Config{})
+	agify := internal.NewAgify(srv.URL + "/agify")
+	ctx := context.Background()
+
+	names := []string{"Ivan", "Zoltan", "Ivan"}
+	first, err := agify.EnrichBatch(ctx, names, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 3 || first[0].Age != 34 || first[2].Age != 34 {
+		t.Fatalf("batch = %+v", first)
+	}
+
+	// Ответы для имён без фикстур детерминированы.
+	second, err := agify.EnrichBatch(ctx, names[1:2], "")
+	if err != nil || second[0].Age != first[1].Age || second[0].Age < 18 {
+		t.Errorf("generated age = %+v, first %+v, %v", second, first[1], err)
+	}
+
+	resp, err := http.Get(srv.URL + "/agify/?name[]=a&name[]=b&name[]=c&name[]=d&name[]=e&name[]=f&name[]=g&name[]=h&name[]=i&name[]=j&name[]=k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("batch over %d names: status %d", internal.MaxBatchSize, resp.StatusCode)
+	}
+}
+
+func TestMockRateLimit(t *testing.T) {
+	srv := mockServer(t, mockenrich.Config{RateLimit: 2, RateWindow: time.Minute})
+
+	get := func(query string) *http.Response {
+		resp, err := http.Get(srv.URL + "/genderize/?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	resp := get("name[]=Ivan&name[]=Anna")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Rate-Limit-Limit") != "2" || resp.Header.Get("X-Rate-Limit-Remaining") != "0" {
+		t.Errorf("within limit: %d %v", resp.StatusCode, resp.Header)
+	}
+	resp = get("name=Ivan")
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" || resp.Header.Get("X-Rate-Limit-Reset") == "" {
+		t.Errorf("over limit: %d %v", resp.StatusCode, resp.Header)
+	}
+	// Квоты провайдеров независимы.
+	resp, err := http.Get(srv.URL + "/agify/?name=Ivan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("agify after genderize limit: status %d", resp.StatusCode)
+	}
+
+	// Клиент получает 429 и сообщает об исчерпанной квоте, не дожидаясь её сброса.
+	genderize := internal.NewGenderize(srv.URL + "/genderize")
+	if _, err := genderize.Enrich(context.Background(), internal.Query{Name: "Ivan"}); !errors.Is(err, internal.ErrQuotaExhausted) {
+		t.Errorf("client over limit: %v", err)
+	}
+}
+
+func TestMockErrorRate(t *testing.T) {
+	srv := mockServer(t, mockenrich.Config{ErrorRate: 1})
+	t.Setenv("NATIONALIZE_MAX_RETRIES", "1")
+	t.Setenv("NATIONALIZE_RETRY_BACKOFF", "1ms")
+
+	_, err := internal.NewNationalize(srv.URL+"/nationalize").Enrich(context.Background(), internal.Query{Name: "Ivan"})
+	var statusErr *internal.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusInternalServerError {
+		t.Errorf("err = %v, want 500", err)
+	}
+}
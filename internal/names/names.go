@@ -0,0 +1,166 @@
+// Package names нормализует и транслитерирует имена, фамилии и отчества.
+package names
+
+import (
+	"fmt"
+	"strings"
+	"task/models"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize приводит имя к каноническому виду: форма NFC, без пробелов по краям,
+// одиночные пробелы между словами, каждое слово и каждая часть через дефис -
+// с заглавной буквы, остальные буквы строчные.
+func Normalize(s string) string {
+	s = strings.Join(strings.Fields(norm.NFC.String(s)), " ")
+
+	runes := []rune(s)
+	start := true
+	for i, r := range runes {
+		if start {
+			runes[i] = unicode.ToUpper(r)
+		} else {
+			runes[i] = unicode.ToLower(r)
+		}
+		start = r == ' ' || r == '-'
+	}
+	return string(runes)
+}
+
+// IsCyrillic сообщает, содержит ли строка кириллические буквы.
+func IsCyrillic(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Standard - система транслитерации кириллицы латиницей.
+type Standard string
+
+const (
+	// ICAO - Doc 9303, используется в заграничных паспортах РФ с 2013 года.
+	ICAO Standard = "icao"
+	// GOST - ГОСТ 7.79-2000, система Б.
+	GOST Standard = "gost"
+)
+
+// ParseStandard разбирает название системы транслитерации без учёта регистра.
+func ParseStandard(s string) (Standard, error) {
+	switch std := Standard(strings.ToLower(strings.TrimSpace(s))); std {
+	case ICAO, GOST:
+		return std, nil
+	}
+	return "", fmt.Errorf("неизвестная система транслитерации: %s", s)
+}
+
+var icao = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "ie", 'ы': "y", 'ь': "", 'э': "e", 'ю': "iu",
+	'я': "ia", 'і': "i", 'ї': "i", 'є': "ie", 'ґ': "g",
+}
+
+var gost = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "j", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "x", 'ц': "cz",
+	'ч': "ch", 'ш': "sh", 'щ': "shh", 'ъ': "``", 'ы': "y`", 'ь': "`", 'э': "e`", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g`",
+}
+
+// ToLatin транслитерирует кириллицу латиницей по выбранной системе. Прочие символы
+// не изменяются. Заглавная буква передаётся заглавной первой буквой сочетания.
+func ToLatin(s string, std Standard) string {
+	table := icao
+	if std == GOST {
+		table = gost
+	}
+
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		lower := unicode.ToLower(r)
+		latin, ok := table[lower]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+
+		// По ГОСТ перед е, и, ы, й буква ц передаётся как c.
+		if std == GOST && lower == 'ц' && i+1 < len(runes) && strings.ContainsRune("еиыйіє", unicode.ToLower(runes[i+1])) {
+			latin = "c"
+		}
+
+		if r != lower && latin != "" {
+			latin = strings.ToUpper(latin[:1]) + latin[1:]
+		}
+		b.WriteString(latin)
+	}
+	return b.String()
+}
+
+// cyrillic - обратная таблица для латиницы. Сочетания проверяются от длинных к коротким.
+var cyrillic = []struct{ latin, cyr string }{
+	{"shch", "щ"}, {"sch", "щ"}, {"shh", "щ"},
+	{"zh", "ж"}, {"kh", "х"}, {"ts", "ц"}, {"cz", "ц"}, {"ch", "ч"}, {"sh", "ш"},
+	{"yu", "ю"}, {"iu", "ю"}, {"ya", "я"}, {"ia", "я"}, {"yo", "ё"}, {"ye", "е"},
+	{"iya", "ия"}, {"iy", "ий"}, {"yy", "ый"},
+	{"a", "а"}, {"b", "б"}, {"c", "ц"}, {"d", "д"}, {"e", "е"}, {"f", "ф"}, {"g", "г"},
+	{"h", "х"}, {"i", "и"}, {"j", "й"}, {"k", "к"}, {"l", "л"}, {"m", "м"}, {"n", "н"},
+	{"o", "о"}, {"p", "п"}, {"q", "к"}, {"r", "р"}, {"s", "с"}, {"t", "т"}, {"u", "у"},
+	{"v", "в"}, {"w", "в"}, {"x", "кс"}, {"y", "ы"}, {"z", "з"},
+}
+
+// ToCyrillic выполняет обратную транслитерацию латиницы в кириллицу. Преобразование
+// приблизительное: разные системы транслитерации неоднозначны.
+func ToCyrillic(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		matched := false
+		for _, m := range cyrillic {
+			n := len(m.latin)
+			if i+n > len(runes) || strings.ToLower(string(runes[i:i+n])) != m.latin {
+				continue
+			}
+
+			cyr := m.cyr
+			if unicode.IsUpper(runes[i]) {
+				first := []rune(cyr)
+				cyr = string(unicode.ToUpper(first[0])) + string(first[1:])
+			}
+			b.WriteString(cyr)
+			i += n
+			matched = true
+			break
+		}
+		if !matched {
+			b.WriteRune(runes[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// Lookup возвращает вариант имени для запросов к провайдерам: кириллица
+// транслитерируется по выбранной системе, латиница остаётся без изменений.
+func Lookup(s string, std Standard) string {
+	if !IsCyrillic(s) {
+		return s
+	}
+	return ToLatin(s, std)
+}
+
+// Apply нормализует имя, фамилию и отчество записи, сохраняя исходный ввод в p.Original.
+func Apply(p *models.Person) {
+	p.Original = models.FullName{Name: p.Name, Surname: p.Surname, Patronymic: p.Patronymic}
+	p.Name = Normalize(p.Name)
+	p.Surname = Normalize(p.Surname)
+	p.Patronymic = Normalize(p.Patronymic)
+}
@@ -0,0 +1,54 @@
+package names
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"  иВАН ", "Иван"},
+		{"анна-мария", "Анна-Мария"},
+		{"ван  дер\tберг", "Ван Дер Берг"},
+		{"андреи\u0306", "Андрей"}, // й в разложенной форме
+		{"o'brien", "O'brien"},
+		{"JOHN", "John"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToLatin(t *testing.T) {
+	tests := []struct {
+		in   string
+		std  Standard
+		want string
+	}{
+		{"Щукин", ICAO, "Shchukin"},
+		{"Юлия", ICAO, "Iuliia"},
+		{"Дарья", ICAO, "Daria"},
+		{"Андрей", ICAO, "Andrei"},
+		{"Пётр", ICAO, "Petr"},
+		{"Ivan-Пётр", ICAO, "Ivan-Petr"},
+		{"Щукин", GOST, "Shhukin"},
+		{"Цыганов", GOST, "Cy`ganov"},
+		{"Царёв", GOST, "Czaryov"},
+		{"Юрьев", GOST, "Yur`ev"},
+		{"Андрей", GOST, "Andrej"},
+	}
+	for _, tt := range tests {
+		if got := ToLatin(tt.in, tt.std); got != tt.want {
+			t.Errorf("ToLatin(%q, %s) = %q, want %q", tt.in, tt.std, got, tt.want)
+		}
+	}
+}
+
+func TestParseStandard(t *testing.T) {
+	if std, err := ParseStandard(" GOST "); err != nil || std != GOST {
+		t.Errorf("ParseStandard(GOST) = %q, %v", std, err)
+	}
+	if _, err := ParseStandard("bgn"); err == nil {
+		t.Error("ParseStandard(bgn) succeeded")
+	}
+}
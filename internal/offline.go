@@ -0,0 +1,213 @@
+package internal
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"task/models"
+	"time"
+)
+
+// NameStats - статистика по одному имени из локального набора данных.
+type NameStats struct {
+	Name string `json:"name"`
+	// Ages - распределение возраста: возраст -> число наблюдений.
+	Ages map[int]int `json:"ages"`
+	// Male - доля мужчин среди носителей имени (от 0 до 1), nil если неизвестна.
+	Male *float64 `json:"male"`
+	// Countries - вероятности стран: код ISO 3166-1 alpha-2 -> вероятность.
+	Countries map[string]float64 `json:"countries"`
+}
+
+// Offline отвечает на те же вопросы, что agify, genderize и nationalize, по
+// локальному набору данных, не обращаясь к сети.
+type Offline struct {
+	stats map[string]NameStats
+}
+
+// LoadOffline создаёт офлайн-провайдера по набору данных, см. LoadNameStats.
+func LoadOffline(path string) (*Offline, error) {
+	if path == "" {
+		return nil, fmt.Errorf("не задан путь к набору данных OFFLINE_DATASET")
+	}
+
+	stats, err := LoadNameStats(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewOffline(stats), nil
+}
+
+// LoadNameStats загружает набор данных из JSON-файла (массив NameStats) или CSV-файла
+// со строками name,field,value,weight, где field - age (value - возраст, weight - число
+// наблюдений), gender (value - male или female, weight - доля) или country
+// (value - код страны, weight - вероятность).
+func LoadNameStats(path string) ([]NameStats, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var stats []NameStats
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.NewDecoder(f).Decode(&stats)
+	case ".csv":
+		stats, err = readOfflineCSV(f)
+	default:
+		err = fmt.Errorf("неподдерживаемый формат набора данных: %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func NewOffline(stats []NameStats) *Offline {
+	o := &Offline{stats: make(map[string]NameStats, len(stats))}
+	for _, s := range stats {
+		o.stats[strings.ToLower(strings.TrimSpace(s.Name))] = s
+	}
+	return o
+}
+
+func readOfflineCSV(r io.Reader) ([]NameStats, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 4
+	cr.Comment = '#'
+
+	byName := map[string]*NameStats{}
+	var order []string
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name, field, value := strings.TrimSpace(record[0]), strings.TrimSpace(record[1]), strings.TrimSpace(record[2])
+		if line == 1 && name == "name" {
+			continue
+		}
+
+		weight, err := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("строка %d: некорректный вес: %v", line, err)
+		}
+
+		s, ok := byName[name]
+		if !ok {
+			s = &NameStats{Name: name, Ages: map[int]int{}, Countries: map[string]float64{}}
+			byName[name] = s
+			order = append(order, name)
+		}
+
+		switch field {
+		case "age":
+			age, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("строка %d: некорректный возраст: %v", line, err)
+			}
+			s.Ages[age] += int(weight)
+		case "gender":
+			male := weight
+			if value == "female" {
+				male = 1 - weight
+			} else if value != "male" {
+				return nil, fmt.Errorf("строка %d: некорректный пол: %s", line, value)
+			}
+			s.Male = &male
+		case "country":
+			s.Countries[strings.ToUpper(value)] = weight
+		default:
+			return nil, fmt.Errorf("строка %d: неизвестное поле: %s", line, field)
+		}
+	}
+
+	stats := make([]NameStats, 0, len(order))
+	for _, name := range order {
+		stats = append(stats, *byName[name])
+	}
+	return stats, nil
+}
+
+func (o *Offline) Name() string { return "offline" }
+
+func (o *Offline) Enrich(ctx context.Context, q Query) (Result, error) {
+	s, ok := o.stats[strings.ToLower(strings.TrimSpace(q.Name))]
+	if !ok {
+		return Result{}, nil
+	}
+
+	var res Result
+	fetchedAt := time.Now()
+
+	if len(s.Ages) > 0 {
+		total, sum := 0, 0
+		for age, count := range s.Ages {
+			total += count
+			sum += age * count
+		}
+		if total > 0 {
+			res.Age = int(math.Round(float64(sum) / float64(total)))
+			res.Details = append(res.Details, models.PersonEnrichment{
+				Provider:  o.Name(),
+				Field:     models.FieldAge,
+				Value:     strconv.Itoa(res.Age),
+				Count:     total,
+				FetchedAt: fetchedAt,
+			})
+		}
+	}
+
+	if s.Male != nil {
+		gender, probability := models.Male, *s.Male
+		if probability < 0.5 {
+			gender, probability = models.Female, 1-probability
+		}
+		res.Gender = gender
+		res.Details = append(res.Details, models.PersonEnrichment{
+			Provider:    o.Name(),
+			Field:       models.FieldGender,
+			Value:       gender.String(),
+			Probability: probability,
+			FetchedAt:   fetchedAt,
+		})
+	}
+
+	countries := make([]string, 0, len(s.Countries))
+	for c := range s.Countries {
+		countries = append(countries, c)
+	}
+	sort.Slice(countries, func(i, j int) bool {
+		if s.Countries[countries[i]] != s.Countries[countries[j]] {
+			return s.Countries[countries[i]] > s.Countries[countries[j]]
+		}
+		return countries[i] < countries[j]
+	})
+	if len(countries) > 0 {
+		res.Nationality = countries[0]
+	}
+	for _, c := range countries {
+		res.Details = append(res.Details, models.PersonEnrichment{
+			Provider:    o.Name(),
+			Field:       models.FieldNationality,
+			Value:       c,
+			Probability: s.Countries[c],
+			FetchedAt:   fetchedAt,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"strings"
+	"task/models"
+	"testing"
+)
+
+func TestLoadNameStatsCSV(t *testing.T) {
+	stats, err := LoadNameStats("testdata/names.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 || stats[0].Name != "Ivan" || stats[1].Name != "Anna" {
+		t.Fatalf("stats = %+v, want Ivan and Anna in file order", stats)
+	}
+
+	ivan := stats[0]
+	if !reflect.DeepEqual(ivan.Ages, map[int]int{30: 120, 40: 80}) {
+		t.Errorf("ages = %v", ivan.Ages)
+	}
+	if !reflect.DeepEqual(ivan.Countries, map[string]float64{"RU": 0.6, "UA": 0.3}) {
+		t.Errorf("countries = %v, want upper-case codes", ivan.Countries)
+	}
+	if anna := stats[1]; anna.Male == nil || math.Abs(*anna.Male-0.1) > 1e-9 {
+		t.Errorf("female share 0.9 was not converted to male share 0.1: %v", anna.Male)
+	}
+}
+
+func TestReadOfflineCSVErrors(t *testing.T) {
+	tests := []struct {
+		name, csv, want string
+	}{
+		{"bad weight", "name,field,value,weight\nIvan,age,30,много\n", "строка 2: некорректный вес"},
+		{"bad age", "Ivan,age,тридцать,1\n", "строка 1: некорректный возраст"},
+		{"bad gender", "Ivan,gender,other,1\n", "строка 1: некорректный пол"},
+		{"unknown field", "Ivan,height,180,1\n", "строка 1: неизвестное поле"},
+		{"wrong field count", "Ivan,age,30\n", "wrong number of fields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readOfflineCSV(strings.NewReader(tt.csv))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// Заголовок пропускается только в первой строке.
+	if _, err := readOfflineCSV(strings.NewReader("Ivan,age,30,1\nname,field,value,weight\n")); err == nil {
+		t.Error("header in the middle of the file was accepted")
+	}
+}
+
+func TestLoadNameStatsFormats(t *testing.T) {
+	if _, err := LoadNameStats("testdata/names.txt"); err == nil {
+		t.Error("missing file loaded")
+	}
+	if _, err := LoadOffline(""); err == nil {
+		t.Error("empty dataset path accepted")
+	}
+
+	fromCSV, err := LoadOffline("testdata/names.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := LoadOffline("testdata/names.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Ivan", "Anna"} {
+		a, _ := fromCSV.Enrich(context.Background(), Query{Name: name})
+		b, _ := fromJSON.Enrich(context.Background(), Query{Name: name})
+		if a.Age != b.Age || a.Gender != b.Gender || a.Nationality != b.Nationality {
+			t.Errorf("%s: csv %+v, json %+v", name, a, b)
+		}
+	}
+}
+
+func TestOfflineEnrich(t *testing.T) {
+	o, err := LoadOffline("testdata/names.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	ivan, _ := o.Enrich(ctx, Query{Name: " ivan "})
+	if ivan.Age != 34 || ivan.Gender != models.Male || ivan.Nationality != "RU" {
+		t.Errorf("ivan = %+v", ivan)
+	}
+	if len(ivan.Details) != 4 || ivan.Details[0].Count != 200 || ivan.Details[2].Value != "RU" || ivan.Details[3].Value != "UA" {
+		t.Errorf("ivan details = %+v", ivan.Details)
+	}
+
+	anna, _ := o.Enrich(ctx, Query{Name: "Anna"})
+	if anna.Gender != models.Female || math.Abs(anna.Details[1].Probability-0.9) > 1e-9 {
+		t.Errorf("anna gender = %s (%v), want female 0.9", anna.Gender, anna.Details[1].Probability)
+	}
+	// При равной вероятности страны упорядочиваются по коду.
+	if anna.Nationality != "BY" || anna.Details[2].Value != "BY" || anna.Details[3].Value != "KZ" {
+		t.Errorf("anna nationality = %s, details %+v", anna.Nationality, anna.Details)
+	}
+
+	if unknown, err := o.Enrich(ctx, Query{Name: "Zzz"}); err != nil || !reflect.DeepEqual(unknown, Result{}) {
+		t.Errorf("unknown name = %+v, %v", unknown, err)
+	}
+}
@@ -0,0 +1,221 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"task/config"
+	"task/internal/names"
+	"task/models"
+	"time"
+)
+
+// lookupName транслитерирует кириллическое имя латиницей по системе из ENRICH_TRANSLIT
+// (icao, gost или none), поскольку публичные API лучше знают латинские написания.
+func lookupName(name string) string {
+	translit := envOr("ENRICH_TRANSLIT", config.EnrichTranslit)
+	if translit == "none" {
+		return name
+	}
+	std, err := names.ParseStandard(translit)
+	if err != nil {
+		std = names.ICAO
+	}
+	return names.Lookup(name, std)
+}
+
+func nameURL(baseURL, name, countryID string) string {
+	v := url.Values{"name": {lookupName(name)}}
+	if countryID != "" {
+		v.Set("country_id", countryID)
+	}
+	return baseURL + "/?" + v.Encode()
+}
+
+func batchURL(baseURL string, batch []string, countryID string) string {
+	lookup := make([]string, len(batch))
+	for i, name := range batch {
+		lookup[i] = lookupName(name)
+	}
+
+	v := url.Values{"name[]": lookup}
+	if countryID != "" {
+		v.Set("country_id", countryID)
+	}
+	return baseURL + "/?" + v.Encode()
+}
+
+// fetchBatch запрашивает несколько имён за раз и проверяет, что ответ содержит по записи на имя.
+func fetchBatch[T any](ctx context.Context, client *Client, rawURL string, count int) ([]T, error) {
+	var data []T
+	if err := client.GetJSON(ctx, rawURL, &data); err != nil {
+		return nil, err
+	}
+	if len(data) != count {
+		return nil, fmt.Errorf("ожидалось %d записей в ответе, получено %d", count, len(data))
+	}
+	return data, nil
+}
+
+// Agify определяет возраст через api.agify.io.
+type Agify struct {
+	BaseURL string
+	client  *Client
+}
+
+func NewAgify(baseURL string) *Agify {
+	return &Agify{BaseURL: baseURL, client: NewClient("agify", LoadClientConfig("agify"))}
+}
+
+func (a *Agify) Name() string { return "agify" }
+
+func (a *Agify) UsesCountry() bool { return true }
+
+func (a *Agify) Enrich(ctx context.Context, q Query) (Result, error) {
+	var data models.PersonWihtAge
+	if err := a.client.GetJSON(ctx, nameURL(a.BaseURL, q.Name, q.CountryID), &data); err != nil {
+		return Result{}, err
+	}
+	return ageResult(data), nil
+}
+
+func (a *Agify) EnrichBatch(ctx context.Context, names []string, countryID string) ([]Result, error) {
+	data, err := fetchBatch[models.PersonWihtAge](ctx, a.client, batchURL(a.BaseURL, names, countryID), len(names))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(data))
+	for i, d := range data {
+		results[i] = ageResult(d)
+	}
+	return results, nil
+}
+
+func ageResult(data models.PersonWihtAge) Result {
+	return Result{
+		Age: data.Age,
+		Details: []models.PersonEnrichment{{
+			Provider:  "agify",
+			Field:     models.FieldAge,
+			Value:     strconv.Itoa(data.Age),
+			Count:     data.Count,
+			FetchedAt: time.Now(),
+		}},
+	}
+}
+
+// Genderize определяет пол через api.genderize.io.
+type Genderize struct {
+	BaseURL string
+	client  *Client
+}
+
+func NewGenderize(baseURL string) *Genderize {
+	return &Genderize{BaseURL: baseURL, client: NewClient("genderize", LoadClientConfig("genderize"))}
+}
+
+func (g *Genderize) Name() string { return "genderize" }
+
+func (g *Genderize) UsesCountry() bool { return true }
+
+func (g *Genderize) Enrich(ctx context.Context, q Query) (Result, error) {
+	var data models.PersonWihtGender
+	if err := g.client.GetJSON(ctx, nameURL(g.BaseURL, q.Name, q.CountryID), &data); err != nil {
+		return Result{}, err
+	}
+	return genderResult(data), nil
+}
+
+func (g *Genderize) EnrichBatch(ctx context.Context, names []string, countryID string) ([]Result, error) {
+	data, err := fetchBatch[models.PersonWihtGender](ctx, g.client, batchURL(g.BaseURL, names, countryID), len(names))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(data))
+	for i, d := range data {
+		results[i] = genderResult(d)
+	}
+	return results, nil
+}
+
+func genderResult(data models.PersonWihtGender) Result {
+	gender := models.Unknown
+	switch data.Gender {
+	case "male":
+		gender = models.Male
+	case "female":
+		gender = models.Female
+	}
+	return Result{
+		Gender: gender,
+		Details: []models.PersonEnrichment{{
+			Provider:    "genderize",
+			Field:       models.FieldGender,
+			Value:       gender.String(),
+			Probability: data.Probability,
+			Count:       data.Count,
+			FetchedAt:   time.Now(),
+		}},
+	}
+}
+
+// Nationalize определяет наиболее вероятную национальность через api.nationalize.io.
+type Nationalize struct {
+	BaseURL string
+	client  *Client
+}
+
+func NewNationalize(baseURL string) *Nationalize {
+	return &Nationalize{BaseURL: baseURL, client: NewClient("nationalize", LoadClientConfig("nationalize"))}
+}
+
+func (n *Nationalize) Name() string { return "nationalize" }
+
+func (n *Nationalize) Enrich(ctx context.Context, q Query) (Result, error) {
+	var data models.PersonWihtNationality
+	if err := n.client.GetJSON(ctx, nameURL(n.BaseURL, q.Name, ""), &data); err != nil {
+		return Result{}, err
+	}
+	return nationalityResult(data), nil
+}
+
+func (n *Nationalize) EnrichBatch(ctx context.Context, names []string, countryID string) ([]Result, error) {
+	data, err := fetchBatch[models.PersonWihtNationality](ctx, n.client, batchURL(n.BaseURL, names, ""), len(names))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(data))
+	for i, d := range data {
+		results[i] = nationalityResult(d)
+	}
+	return results, nil
+}
+
+func nationalityResult(data models.PersonWihtNationality) Result {
+	nationality := ""
+	if len(data.Nationality) > 0 {
+		sort.Slice(data.Nationality, func(i, j int) bool {
+			return data.Nationality[i].Probability > data.Nationality[j].Probability
+		})
+		nationality = data.Nationality[0].CountryID
+	}
+
+	fetchedAt := time.Now()
+	details := make([]models.PersonEnrichment, 0, len(data.Nationality))
+	for _, c := range data.Nationality {
+		details = append(details, models.PersonEnrichment{
+			Provider:    "nationalize",
+			Field:       models.FieldNationality,
+			Value:       c.CountryID,
+			Probability: c.Probability,
+			Count:       data.Count,
+			FetchedAt:   fetchedAt,
+		})
+	}
+	return Result{Nationality: nationality, Details: details}
+}
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrQuotaExhausted = errors.New("квота запросов к провайдеру исчерпана")
+
+// QuotaError возвращается без обращения к провайдеру, пока его квота исчерпана.
+type QuotaError struct {
+	Provider string
+	ResetAt  time.Time
+}
+
+func (e *QuotaError) Error() string {
+	return ErrQuotaExhausted.Error() + " до " + e.ResetAt.Format(time.RFC3339)
+}
+
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExhausted
+}
+
+// QuotaStatus - текущая квота провайдера по заголовкам X-Rate-Limit-*.
+type QuotaStatus struct {
+	Provider  string     `json:"provider"`
+	Known     bool       `json:"known"`
+	Limit     int        `json:"limit"`
+	Remaining int        `json:"remaining"`
+	ResetAt   *time.Time `json:"reset_at,omitempty"`
+	Exhausted bool       `json:"exhausted"`
+}
+
+// Quota отслеживает остаток запросов провайдера. Когда остаток опускается до
+// reserve, запросы блокируются локально до момента сброса квоты.
+type Quota struct {
+	mu        sync.Mutex
+	reserve   int
+	window    time.Duration
+	known     bool
+	limit     int
+	remaining int
+	resetAt   time.Time
+}
+
+// NewQuota создаёт квоту. window - время блокировки после ответа 429, в котором
+// провайдер не сообщил момент сброса квоты.
+func NewQuota(reserve int, window time.Duration) *Quota {
+	return &Quota{reserve: reserve, window: window}
+}
+
+func (q *Quota) Allow(provider string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.exhausted() {
+		return &QuotaError{Provider: provider, ResetAt: q.resetAt}
+	}
+	return nil
+}
+
+func (q *Quota) exhausted() bool {
+	return q.known && q.remaining <= q.reserve && time.Now().Before(q.resetAt)
+}
+
+// Update обновляет квоту по заголовкам ответа. Ответ 429 считается исчерпанием
+// квоты до Retry-After, X-Rate-Limit-Reset или, если их нет, на время window.
+func (q *Quota) Update(resp *http.Response) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	h := resp.Header
+	var resetAt time.Time
+	if reset, err := strconv.Atoi(h.Get("X-Rate-Limit-Reset")); err == nil {
+		resetAt = time.Now().Add(time.Duration(reset) * time.Second)
+	}
+	if remaining, err := strconv.Atoi(h.Get("X-Rate-Limit-Remaining")); err == nil {
+		q.known = true
+		q.remaining = remaining
+		if limit, err := strconv.Atoi(h.Get("X-Rate-Limit-Limit")); err == nil {
+			q.limit = limit
+		}
+		if !resetAt.IsZero() {
+			q.resetAt = resetAt
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter := parseRetryAfter(h.Get("Retry-After")); retryAfter > 0 {
+			resetAt = time.Now().Add(retryAfter)
+		}
+		if resetAt.IsZero() {
+			resetAt = time.Now().Add(q.window)
+		}
+		q.known = true
+		q.remaining = 0
+		q.resetAt = resetAt
+	}
+}
+
+func (q *Quota) Status() QuotaStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	status := QuotaStatus{
+		Known:     q.known,
+		Limit:     q.limit,
+		Remaining: q.remaining,
+		Exhausted: q.exhausted(),
+	}
+	if !q.resetAt.IsZero() {
+		resetAt := q.resetAt
+		status.ResetAt = &resetAt
+	}
+	return status
+}
+
+// QuotaStates возвращает квоты всех провайдеров.
+func QuotaStates() []QuotaStatus {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+
+	states := make([]QuotaStatus, 0, len(clients))
+	for provider, c := range clients {
+		status := c.quota.Status()
+		status.Provider = provider
+		states = append(states, status)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Provider < states[j].Provider
+	})
+	return states
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestQuotaUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      int
+		headers   map[string]string
+		reserve   int
+		exhausted bool
+		resetIn   time.Duration // 0 - момент сброса неизвестен
+	}{
+		{"no headers", 200, nil, 0, false, 0},
+		{"remaining", 200, map[string]string{"X-Rate-Limit-Limit": "100", "X-Rate-Limit-Remaining": "5", "X-Rate-Limit-Reset": "30"}, 0, false, 30 * time.Second},
+		{"remaining within reserve", 200, map[string]string{"X-Rate-Limit-Remaining": "5", "X-Rate-Limit-Reset": "30"}, 5, true, 30 * time.Second},
+		{"exhausted without reset", 200, map[string]string{"X-Rate-Limit-Remaining": "0"}, 0, false, 0},
+		{"429 with Retry-After", 429, map[string]string{"Retry-After": "10"}, 0, true, 10 * time.Second},
+		{"429 with reset", 429, map[string]string{"X-Rate-Limit-Reset": "20"}, 0, true, 20 * time.Second},
+		{"429 without headers", 429, nil, 0, true, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuota(tt.reserve, time.Minute)
+			resp := &http.Response{StatusCode: tt.code, Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+			q.Update(resp)
+
+			status := q.Status()
+			if status.Exhausted != tt.exhausted {
+				t.Errorf("exhausted = %t, want %t", status.Exhausted, tt.exhausted)
+			}
+			err := q.Allow("agify")
+			if tt.exhausted != errors.Is(err, ErrQuotaExhausted) {
+				t.Errorf("Allow = %v", err)
+			}
+			switch {
+			case tt.resetIn == 0 && status.ResetAt != nil:
+				t.Errorf("reset_at = %s, want unknown", status.ResetAt)
+			case tt.resetIn != 0 && (status.ResetAt == nil || time.Until(*status.ResetAt) < tt.resetIn-time.Second || time.Until(*status.ResetAt) > tt.resetIn):
+				t.Errorf("reset_at = %v, want in %s", status.ResetAt, tt.resetIn)
+			}
+		})
+	}
+}
+
+func TestQuotaResets(t *testing.T) {
+	q := NewQuota(0, 10*time.Millisecond)
+	q.Update(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+	var quotaErr *QuotaError
+	if err := q.Allow("agify"); !errors.As(err, &quotaErr) || quotaErr.Provider != "agify" {
+		t.Fatalf("Allow after 429 = %v", err)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if err := q.Allow("agify"); err != nil {
+		t.Errorf("Allow after window = %v", err)
+	}
+}
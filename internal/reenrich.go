@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"context"
+	"task/models"
+)
+
+type refreshKey struct{}
+
+// WithRefresh возвращает контекст, в котором EnrichPerson и EnrichBatch не читают
+// кэш, а всегда обращаются к провайдерам (результаты при этом кэшируются).
+func WithRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+func refresh(ctx context.Context) bool {
+	v, _ := ctx.Value(refreshKey{}).(bool)
+	return v
+}
+
+// OverriddenFields возвращает вычисляемые поля, значения которых заданы вручную или
+// импортированы и не должны перезаписываться обогащением. Для записей без сведений
+// о происхождении поле считается изменённым, если для него сохранены ответы
+// провайдеров, но ни один из них не совпадает с текущим значением.
+func OverriddenFields(p models.Person) []string {
+	seen := map[string]bool{}
+	matched := map[string]bool{}
+	for _, d := range p.Enrichment {
+		seen[d.Field] = true
+		if p.FieldValue(d.Field) == d.Value {
+			matched[d.Field] = true
+		}
+	}
+
+	var overridden []string
+	for _, field := range models.InferredFields {
+		provenance := p.FieldProvenance(field)
+		if provenance.Overridden() || (provenance.Source == "" && seen[field] && !matched[field]) {
+			overridden = append(overridden, field)
+		}
+	}
+	return overridden
+}
+
+// Reapply переносит результат повторного обогащения в запись, не трогая поля,
+// заданные вручную или импортированные. Возвращает список сохранённых полей.
+func (r Result) Reapply(p *models.Person) []string {
+	overridden := OverriddenFields(*p)
+	kept := *p
+
+	r.Apply(p)
+	for _, field := range overridden {
+		switch field {
+		case models.FieldAge:
+			p.Age = kept.Age
+		case models.FieldGender:
+			p.Gender = kept.Gender
+		case models.FieldNationality:
+			p.Nationality = kept.Nationality
+		}
+		*p.FieldProvenance(field) = *kept.FieldProvenance(field)
+	}
+	return overridden
+}
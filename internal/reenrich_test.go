@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"reflect"
+	"task/models"
+	"testing"
+)
+
+func detail(provider, field, value string) models.PersonEnrichment {
+	return models.PersonEnrichment{Provider: provider, Field: field, Value: value}
+}
+
+func TestOverriddenFields(t *testing.T) {
+	enriched := []models.PersonEnrichment{
+		detail("agify", models.FieldAge, "40"),
+		detail("genderize", models.FieldGender, "male"),
+		detail("nationalize", models.FieldNationality, "RU"),
+		detail("nationalize", models.FieldNationality, "KZ"),
+	}
+	tests := []struct {
+		name   string
+		person models.Person
+		want   []string
+	}{
+		{"never enriched", models.Person{Age: 25}, nil},
+		{"provider values", models.Person{
+			Age: 40, AgeProvenance: models.NewProvenance("agify"),
+			Gender: models.Male, GenderProvenance: models.NewProvenance("genderize"),
+			Nationality: "RU", NationalityProvenance: models.NewProvenance("nationalize"),
+			Enrichment: enriched,
+		}, nil},
+		{"manual and import", models.Person{
+			Age: 40, AgeProvenance: models.NewProvenance(models.SourceManual),
+			Gender: models.Male, GenderProvenance: models.NewProvenance("genderize"),
+			Nationality: "KZ", NationalityProvenance: models.NewProvenance(models.SourceImport),
+			Enrichment: enriched,
+		}, []string{models.FieldAge, models.FieldNationality}},
+		// Записи, сохранённые до появления происхождения, сравниваются с ответами провайдеров.
+		{"legacy second candidate", models.Person{Age: 40, Gender: models.Male, Nationality: "KZ", Enrichment: enriched}, nil},
+		{"legacy changed", models.Person{Age: 25, Gender: models.Female, Nationality: "RU", Enrichment: enriched},
+			[]string{models.FieldAge, models.FieldGender}},
+	}
+	for _, tt := range tests {
+		if got := OverriddenFields(tt.person); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: OverriddenFields = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReapplyKeepsOverriddenFields(t *testing.T) {
+	manual := models.NewProvenance(models.SourceManual)
+	p := models.Person{
+		Id:  1,
+		Age: 25, AgeProvenance: manual,
+		Gender: models.Male, GenderProvenance: models.NewProvenance("genderize"),
+		Nationality: "RU", NationalityProvenance: models.NewProvenance("nationalize"),
+	}
+	res := Result{Age: 41, Gender: models.Female, Nationality: "KZ", Details: []models.PersonEnrichment{
+		detail("agify", models.FieldAge, "41"),
+		detail("genderize", models.FieldGender, "female"),
+		detail("nationalize", models.FieldNationality, "KZ"),
+	}}
+
+	kept := res.Reapply(&p)
+	if !reflect.DeepEqual(kept, []string{models.FieldAge}) {
+		t.Errorf("kept = %v, want [age]", kept)
+	}
+	if p.Age != 25 || p.AgeProvenance != manual {
+		t.Errorf("age = %d (%+v), want manual 25", p.Age, p.AgeProvenance)
+	}
+	if p.Gender != models.Female || p.GenderProvenance.Source != "genderize" {
+		t.Errorf("gender = %s (%s), want female from genderize", p.Gender, p.GenderProvenance.Source)
+	}
+	if p.Nationality != "KZ" || p.NationalityProvenance.Source != "nationalize" {
+		t.Errorf("nationality = %s (%s), want KZ from nationalize", p.Nationality, p.NationalityProvenance.Source)
+	}
+	if len(p.Enrichment) != 3 || p.Enrichment[0].PersonId != 1 {
+		t.Errorf("enrichment not replaced: %+v", p.Enrichment)
+	}
+}
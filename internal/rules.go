@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"task/models"
+	"time"
+)
+
+// suffixRule связывает окончание фамилии или отчества со значением поля.
+type suffixRule struct {
+	suffix      string
+	value       string
+	probability float64
+}
+
+// Правила проверяются по порядку, срабатывает первое подходящее. Латинские варианты
+// покрывают распространённые транслитерации.
+var patronymicGenderRules = []suffixRule{
+	{"овна", "female", 0.99}, {"евна", "female", 0.99}, {"ична", "female", 0.99},
+	{"івна", "female", 0.99}, {"ївна", "female", 0.99}, {"кызы", "female", 0.99},
+	{"ovna", "female", 0.99}, {"evna", "female", 0.99}, {"ichna", "female", 0.99},
+	{"ivna", "female", 0.99}, {"kyzy", "female", 0.99}, {"qizi", "female", 0.99},
+	{"ович", "male", 0.99}, {"евич", "male", 0.99}, {"ич", "male", 0.95}, {"оглы", "male", 0.99},
+	{"ovich", "male", 0.99}, {"evich", "male", 0.99}, {"ovych", "male", 0.99}, {"evych", "male", 0.99},
+	{"ich", "male", 0.95}, {"ogly", "male", 0.99}, {"ogli", "male", 0.99}, {"ugli", "male", 0.99},
+}
+
+var surnameGenderRules = []suffixRule{
+	{"ская", "female", 0.95}, {"цкая", "female", 0.95}, {"ова", "female", 0.9}, {"ева", "female", 0.9},
+	{"ёва", "female", 0.9}, {"ина", "female", 0.85}, {"ына", "female", 0.85},
+	{"skaya", "female", 0.95}, {"ova", "female", 0.85}, {"eva", "female", 0.85}, {"ina", "female", 0.7},
+	{"ский", "male", 0.95}, {"цкий", "male", 0.95}, {"ской", "male", 0.9}, {"ов", "male", 0.9},
+	{"ев", "male", 0.9}, {"ёв", "male", 0.9}, {"ин", "male", 0.85}, {"ын", "male", 0.85},
+	{"skiy", "male", 0.95}, {"skii", "male", 0.95}, {"sky", "male", 0.9}, {"ov", "male", 0.85},
+	{"ev", "male", 0.85}, {"in", "male", 0.6},
+}
+
+var patronymicNationalityRules = []suffixRule{
+	{"івна", "UA", 0.6}, {"ївна", "UA", 0.6}, {"ivna", "UA", 0.5},
+	{"оглы", "AZ", 0.6}, {"кызы", "AZ", 0.6}, {"ogly", "AZ", 0.6}, {"kyzy", "AZ", 0.6},
+	{"ogli", "UZ", 0.6}, {"ugli", "UZ", 0.6}, {"qizi", "UZ", 0.6},
+}
+
+var surnameNationalityRules = []suffixRule{
+	{"енко", "UA", 0.7}, {"enko", "UA", 0.7}, {"чук", "UA", 0.6}, {"юк", "UA", 0.6},
+	{"chuk", "UA", 0.6}, {"yuk", "UA", 0.6}, {"iuk", "UA", 0.6},
+	{"швили", "GE", 0.8}, {"дзе", "GE", 0.8}, {"shvili", "GE", 0.8}, {"dze", "GE", 0.8},
+	{"ян", "AM", 0.7}, {"yan", "AM", 0.7}, {"ian", "AM", 0.5},
+	{"ёнок", "BY", 0.6}, {"онок", "BY", 0.6}, {"enok", "BY", 0.5}, {"onok", "BY", 0.5},
+	{"ович", "BY", 0.4}, {"евич", "BY", 0.4}, {"ovich", "BY", 0.4}, {"evich", "BY", 0.4},
+	{"ский", "RU", 0.4}, {"ская", "RU", 0.4}, {"ова", "RU", 0.4}, {"ева", "RU", 0.4},
+	{"ов", "RU", 0.4}, {"ев", "RU", 0.4}, {"ина", "RU", 0.3}, {"ин", "RU", 0.3},
+	{"ova", "RU", 0.3}, {"eva", "RU", 0.3}, {"ov", "RU", 0.3}, {"ev", "RU", 0.3},
+}
+
+// matchSuffix возвращает первое правило, окончание которого совпадает со словом.
+// Слово должно быть длиннее окончания хотя бы на две буквы.
+func matchSuffix(rules []suffixRule, word string) (suffixRule, bool) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	for _, rule := range rules {
+		if strings.HasSuffix(word, rule.suffix) && len([]rune(word)) >= len([]rune(rule.suffix))+2 {
+			return rule, true
+		}
+	}
+	return suffixRule{}, false
+}
+
+// Rules определяет пол и национальность по окончаниям отчества и фамилии,
+// характерным для русских, украинских и других имён стран СНГ. Сеть не используется.
+type Rules struct{}
+
+func NewRules() *Rules {
+	return &Rules{}
+}
+
+func (r *Rules) Name() string { return "rules" }
+
+func (r *Rules) UsesFullName() bool { return true }
+
+func (r *Rules) Enrich(ctx context.Context, q Query) (Result, error) {
+	var res Result
+	fetchedAt := time.Now()
+
+	add := func(rules []suffixRule, word, field string) {
+		if rule, ok := matchSuffix(rules, word); ok {
+			res.Details = append(res.Details, models.PersonEnrichment{
+				Provider:    r.Name(),
+				Field:       field,
+				Value:       rule.value,
+				Probability: rule.probability,
+				FetchedAt:   fetchedAt,
+			})
+		}
+	}
+
+	add(patronymicGenderRules, q.Patronymic, models.FieldGender)
+	add(surnameGenderRules, q.Surname, models.FieldGender)
+	add(patronymicNationalityRules, q.Patronymic, models.FieldNationality)
+	add(surnameNationalityRules, q.Surname, models.FieldNationality)
+
+	res.vote()
+	return res, nil
+}
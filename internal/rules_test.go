@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"context"
+	"task/models"
+	"testing"
+)
+
+func TestMatchSuffix(t *testing.T) {
+	tests := []struct {
+		rules       []suffixRule
+		word        string
+		value       string
+		probability float64
+	}{
+		{patronymicGenderRules, "Иванович", "male", 0.99},
+		{patronymicGenderRules, "Ильич", "male", 0.95},
+		{patronymicGenderRules, "  IVANOVNA ", "female", 0.99},
+		{patronymicGenderRules, "Мамед оглы", "male", 0.99},
+		{surnameGenderRules, "Петрова", "female", 0.9}, // "ова" раньше "ов"
+		{surnameGenderRules, "Петров", "male", 0.9},
+		{surnameGenderRules, "Ов", "", 0},   // слово не длиннее окончания на две буквы
+		{surnameGenderRules, "Иов", "", 0},  // на одну букву
+		{surnameGenderRules, "Смит", "", 0}, // нет подходящего окончания
+		{surnameNationalityRules, "Шевченко", "UA", 0.7},
+		{surnameNationalityRules, "Бериашвили", "GE", 0.8},
+		{surnameNationalityRules, "Petrovich", "BY", 0.4},
+	}
+	for _, tt := range tests {
+		rule, ok := matchSuffix(tt.rules, tt.word)
+		if ok != (tt.value != "") || rule.value != tt.value || rule.probability != tt.probability {
+			t.Errorf("matchSuffix(%q) = %+v, %t, want %s %v", tt.word, rule, ok, tt.value, tt.probability)
+		}
+	}
+}
+
+func TestRulesEnrich(t *testing.T) {
+	res, err := NewRules().Enrich(context.Background(), Query{Name: "Оксана", Surname: "Шевченко", Patronymic: "Петрівна"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Пол - только по отчеству, национальность - UA по отчеству (0.6) и фамилии (0.7).
+	if res.Gender != models.Female || res.Nationality != "UA" || len(res.Details) != 3 {
+		t.Errorf("result = %+v", res)
+	}
+
+	if res, _ := NewRules().Enrich(context.Background(), Query{Name: "John", Surname: "Smith"}); len(res.Details) != 0 || res.Gender != models.Unknown {
+		t.Errorf("no rules matched, got %+v", res)
+	}
+}
+
+func TestWeightedChoice(t *testing.T) {
+	detail := func(provider, value string, probability float64) models.PersonEnrichment {
+		return models.PersonEnrichment{Provider: provider, Field: models.FieldGender, Value: value, Probability: probability}
+	}
+	tests := []struct {
+		name    string
+		env     map[string]string
+		details []models.PersonEnrichment
+		want    string
+	}{
+		{"highest score", nil, []models.PersonEnrichment{detail("genderize", "female", 0.6), detail("rules", "male", 0.5)}, "female"},
+		{"scores add up", nil, []models.PersonEnrichment{detail("genderize", "female", 0.6), detail("rules", "male", 0.5), detail("offline", "male", 0.2)}, "male"},
+		{"tie keeps first", nil, []models.PersonEnrichment{detail("genderize", "male", 0.5), detail("rules", "female", 0.5)}, "male"},
+		{"tie keeps first reversed", nil, []models.PersonEnrichment{detail("rules", "female", 0.5), detail("genderize", "male", 0.5)}, "female"},
+		{"provider weight", map[string]string{"RULES_WEIGHT": "2"}, []models.PersonEnrichment{detail("genderize", "female", 0.6), detail("rules", "male", 0.5)}, "male"},
+		{"common weight", map[string]string{"ENRICH_WEIGHT": "0", "RULES_WEIGHT": "1"}, []models.PersonEnrichment{detail("genderize", "female", 0.9), detail("rules", "male", 0.1)}, "male"},
+		{"unknown is ignored", nil, []models.PersonEnrichment{detail("genderize", "unknown", 1), detail("rules", "female", 0.1)}, "female"},
+		{"nothing to choose", nil, []models.PersonEnrichment{detail("genderize", "", 1)}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			got, ok := weightedChoice(tt.details, models.FieldGender)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("weightedChoice = %q, %t, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestVote(t *testing.T) {
+	res := Result{Gender: models.Male, Nationality: "RU", Details: []models.PersonEnrichment{
+		{Provider: "genderize", Field: models.FieldGender, Value: "male", Probability: 0.55},
+		{Provider: "rules", Field: models.FieldGender, Value: "female", Probability: 0.9},
+		{Provider: "nationalize", Field: models.FieldNationality, Value: "RU", Probability: 0.3},
+		{Provider: "nationalize", Field: models.FieldNationality, Value: "UA", Probability: 0.2},
+		{Provider: "rules", Field: models.FieldNationality, Value: "UA", Probability: 0.7},
+	}}
+	res.vote()
+	if res.Gender != models.Female || res.Nationality != "UA" {
+		t.Errorf("vote = %s, %s, want female UA", res.Gender, res.Nationality)
+	}
+}
@@ -1,17 +1,26 @@
 package models
 
+import (
+	"strconv"
+	"time"
+)
+
 type PersonWihtAge struct {
-	Name string `json:"name"`
-	Age  int    `json:"age"`
+	Name  string `json:"name"`
+	Age   int    `json:"age"`
+	Count int    `json:"count"`
 }
 
 type PersonWihtGender struct {
-	Name   string `json:"name"`
-	Gender string `json:"gender"`
+	Name        string  `json:"name"`
+	Gender      string  `json:"gender"`
+	Probability float64 `json:"probability"`
+	Count       int     `json:"count"`
 }
 
 type PersonWihtNationality struct {
 	Name        string `json:"name"`
+	Count       int    `json:"count"`
 	Nationality []struct {
 		CountryID   string  `json:"country_id"`
 		Probability float64 `json:"probability"`
@@ -37,21 +46,145 @@ func (g Gender) String() string {
 	}
 }
 
+// ParseGender разбирает пол по названию (male, female, unknown) или числовому значению.
+func ParseGender(s string) (Gender, bool) {
+	for g := Unknown; g <= Female; g++ {
+		if s == g.String() || s == strconv.Itoa(int(g)) {
+			return g, true
+		}
+	}
+	return Unknown, false
+}
+
+// Person - запись о человеке. Правила проверки входных данных заданы в тегах
+// validate, см. пакет validation.
 type Person struct {
-	Id          int    `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name        string `json:"name" gorm:"type:varchar(100);not null"`
-	Surname     string `json:"surname" gorm:"type:varchar(100);not null"`
-	Patronymic  string `json:"patronymic,omitempty" gorm:"type:varchar(100)"`
-	Age         int    `json:"age,omitempty" gorm:"default:0"`
-	Gender      Gender `json:"gender,omitempty" gorm:"type:integer"`
-	Nationality string `json:"nationality,omitempty" gorm:"type:varchar(50)"`
+	Id          int    `json:"id" gorm:"primaryKey;autoIncrement" validate:"readonly"`
+	Name        string `json:"name" gorm:"type:varchar(100);not null" validate:"required,max=100,name"`
+	Surname     string `json:"surname" gorm:"type:varchar(100);not null" validate:"required,max=100,name"`
+	Patronymic  string `json:"patronymic,omitempty" gorm:"type:varchar(100)" validate:"max=100,name"`
+	Age         int    `json:"age,omitempty" gorm:"default:0" validate:"computed,min=0,max=150"`
+	Gender      Gender `json:"gender,omitempty" gorm:"type:integer" validate:"computed,min=0,max=2"`
+	Nationality string `json:"nationality,omitempty" gorm:"type:varchar(50)" validate:"computed,country"`
+
+	// Original - имя, фамилия и отчество в том виде, в каком их передал клиент, до нормализации.
+	Original FullName `json:"original" gorm:"embedded;embedded_prefix:original_" validate:"readonly"`
+
+	AgeProvenance         Provenance `json:"age_provenance" gorm:"embedded;embedded_prefix:age_" validate:"readonly"`
+	GenderProvenance      Provenance `json:"gender_provenance" gorm:"embedded;embedded_prefix:gender_" validate:"readonly"`
+	NationalityProvenance Provenance `json:"nationality_provenance" gorm:"embedded;embedded_prefix:nationality_" validate:"readonly"`
+
+	EnrichmentStatus string             `json:"enrichment_status,omitempty" gorm:"type:varchar(20);default:'done'" validate:"readonly"`
+	EnrichmentError  string             `json:"enrichment_error,omitempty" gorm:"type:text" validate:"readonly"`
+	Enrichment       []PersonEnrichment `json:"enrichment,omitempty" gorm:"foreignkey:PersonId" validate:"readonly"`
+}
+
+const (
+	EnrichmentPending = "pending"
+	EnrichmentDone    = "done"
+	EnrichmentFailed  = "failed"
+)
+
+const (
+	FieldAge         = "age"
+	FieldGender      = "gender"
+	FieldNationality = "nationality"
+)
+
+// InferredFields - поля Person, вычисляемые при обогащении.
+var InferredFields = []string{FieldAge, FieldGender, FieldNationality}
+
+// FieldValue возвращает вычисляемое поле в том же строковом виде, в котором
+// провайдеры сохраняют значения в PersonEnrichment.
+func (p Person) FieldValue(field string) string {
+	switch field {
+	case FieldAge:
+		return strconv.Itoa(p.Age)
+	case FieldGender:
+		return p.Gender.String()
+	case FieldNationality:
+		return p.Nationality
+	}
+	return ""
+}
+
+// FieldProvenance возвращает происхождение вычисляемого поля или nil для прочих полей.
+func (p *Person) FieldProvenance(field string) *Provenance {
+	switch field {
+	case FieldAge:
+		return &p.AgeProvenance
+	case FieldGender:
+		return &p.GenderProvenance
+	case FieldNationality:
+		return &p.NationalityProvenance
+	}
+	return nil
+}
+
+type FullName struct {
+	Name       string `json:"name,omitempty" gorm:"type:text"`
+	Surname    string `json:"surname,omitempty" gorm:"type:text"`
+	Patronymic string `json:"patronymic,omitempty" gorm:"type:text"`
+}
+
+const (
+	SourceManual = "manual"
+	SourceImport = "import"
+)
+
+// Provenance - происхождение значения вычисляемого поля: имя провайдера обогащения,
+// manual (изменено через API) или import (передано при массовом создании).
+type Provenance struct {
+	Source string     `json:"source,omitempty" gorm:"type:varchar(50)"`
+	SetAt  *time.Time `json:"set_at,omitempty"`
+}
+
+func NewProvenance(source string) Provenance {
+	now := time.Now()
+	return Provenance{Source: source, SetAt: &now}
+}
+
+// Overridden сообщает, задано ли значение человеком, а не провайдером обогащения.
+func (p Provenance) Overridden() bool {
+	return p.Source == SourceManual || p.Source == SourceImport
+}
+
+// PersonEnrichment - сырой ответ провайдера обогащения с оценкой достоверности.
+// Для nationalize сохраняется по записи на каждую страну-кандидата.
+type PersonEnrichment struct {
+	Id          int       `json:"-" gorm:"primary_key"`
+	PersonId    int       `json:"-" gorm:"not null"`
+	Provider    string    `json:"provider" gorm:"type:varchar(50);not null"`
+	Field       string    `json:"field" gorm:"type:varchar(20);not null"`
+	Value       string    `json:"value" gorm:"type:varchar(100)"`
+	Probability float64   `json:"probability,omitempty"`
+	Count       int       `json:"count,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+func (PersonEnrichment) TableName() string {
+	return "person_enrichment"
 }
 
 type UpdatePerson struct {
-	Name        *string `json:"name,omitempty"`
-	Surname     *string `json:"surname,omitempty"`
-	Patronymic  *string `json:"patronymic,omitempty"`
-	Age         *int    `json:"age,omitempty"`
-	Gender      *Gender `json:"gender,omitempty"`
-	Nationality *string `json:"nationality,omitempty"`
+	Name        *string `json:"name,omitempty" validate:"required,max=100,name"`
+	Surname     *string `json:"surname,omitempty" validate:"required,max=100,name"`
+	Patronymic  *string `json:"patronymic,omitempty" validate:"max=100,name"`
+	Age         *int    `json:"age,omitempty" validate:"min=0,max=150"`
+	Gender      *Gender `json:"gender,omitempty" validate:"min=0,max=2"`
+	Nationality *string `json:"nationality,omitempty" validate:"country"`
+}
+
+type EnrichmentCache struct {
+	// Name - ключ кэша: имя в нижнем регистре и код страны через "|", если он задан.
+	Name        string    `gorm:"primary_key;type:text"`
+	Age         int       `gorm:"default:0"`
+	Gender      Gender    `gorm:"type:integer"`
+	Nationality string    `gorm:"type:varchar(50)"`
+	Details     string    `gorm:"type:text"`
+	FetchedAt   time.Time `gorm:"not null"`
+}
+
+func (EnrichmentCache) TableName() string {
+	return "enrichment_cache"
 }
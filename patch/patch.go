@@ -0,0 +1,314 @@
+// Package patch применяет к JSON-документам JSON Merge Patch (RFC 7396)
+// и JSON Patch (RFC 6902).
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalid - тело патча не является корректным документом своего формата.
+// Прочие ошибки означают, что корректный патч нельзя применить к документу.
+var ErrInvalid = errors.New("invalid patch document")
+
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// Merge применяет JSON Merge Patch к документу doc.
+func Merge(doc, patch []byte) ([]byte, error) {
+	var target, p any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %v", err)
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return json.Marshal(merge(target, p))
+}
+
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+			continue
+		}
+		t[key] = merge(t[key], value)
+	}
+	return t
+}
+
+// Operation - одна операция JSON Patch.
+type Operation struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from,omitempty"`
+	Value *json.RawMessage `json:"value,omitempty"`
+}
+
+// Apply применяет JSON Patch к документу doc. Операции выполняются по порядку;
+// при ошибке любой из них документ не изменяется.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %v", err)
+	}
+
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	for i, op := range ops {
+		var err error
+		target, err = apply(target, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %v", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+func apply(doc any, op Operation) (any, error) {
+	value := func() (any, error) {
+		if op.Value == nil {
+			return nil, fmt.Errorf("value is required")
+		}
+		var v any
+		err := json.Unmarshal(*op.Value, &v)
+		return v, err
+	}
+
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, v)
+	case "remove":
+		doc, _, err := remove(doc, op.Path)
+		return doc, err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		doc, _, err = remove(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, v)
+	case "move":
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move a value into its own child")
+		}
+		doc, v, err := remove(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, v)
+	case "copy":
+		v, err := get(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, deepCopy(v))
+	case "test":
+		expected, err := value()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := get(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// parsePointer разбирает JSON Pointer (RFC 6901) на токены.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > length || (!allowEnd && i == length) {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+func get(doc any, pointer string) (any, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tokens {
+		switch node := doc.(type) {
+		case map[string]any:
+			v, ok := node[t]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", pointer)
+			}
+			doc = v
+		case []any:
+			i, err := arrayIndex(t, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("path %q not found", pointer)
+		}
+	}
+	return doc, nil
+}
+
+// update заменяет значение родителя последнего токена пути результатом change.
+func update(doc any, pointer string, change func(parent any, last string) (any, error)) (any, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return change(nil, "")
+	}
+
+	parentPointer := pointer[:strings.LastIndex(pointer, "/")]
+	parent, err := get(doc, parentPointer)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := change(parent, tokens[len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return updated, nil
+	}
+	return set(doc, parentPointer, updated)
+}
+
+// set записывает значение по существующему пути.
+func set(doc any, pointer string, value any) (any, error) {
+	return update(doc, pointer, func(parent any, last string) (any, error) {
+		switch node := parent.(type) {
+		case nil:
+			return value, nil
+		case map[string]any:
+			node[last] = value
+			return node, nil
+		case []any:
+			i, err := arrayIndex(last, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			node[i] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("path %q not found", pointer)
+	})
+}
+
+func add(doc any, pointer string, value any) (any, error) {
+	return update(doc, pointer, func(parent any, last string) (any, error) {
+		switch node := parent.(type) {
+		case nil:
+			return value, nil
+		case map[string]any:
+			node[last] = value
+			return node, nil
+		case []any:
+			i, err := arrayIndex(last, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node[:i], append([]any{value}, node[i:]...)...)
+			return node, nil
+		}
+		return nil, fmt.Errorf("path %q not found", pointer)
+	})
+}
+
+func remove(doc any, pointer string) (any, any, error) {
+	var removed any
+	doc, err := update(doc, pointer, func(parent any, last string) (any, error) {
+		switch node := parent.(type) {
+		case nil:
+			return nil, fmt.Errorf("cannot remove the whole document")
+		case map[string]any:
+			v, ok := node[last]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", pointer)
+			}
+			removed = v
+			delete(node, last)
+			return node, nil
+		case []any:
+			i, err := arrayIndex(last, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[i]
+			return append(node[:i], node[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("path %q not found", pointer)
+	})
+	return doc, removed, err
+}
+
+func deepCopy(v any) any {
+	switch node := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(node))
+		for k, value := range node {
+			out[k] = deepCopy(value)
+		}
+		return out
+	case []any:
+		out := make([]any, len(node))
+		for i, value := range node {
+			out[i] = deepCopy(value)
+		}
+		return out
+	}
+	return v
+}
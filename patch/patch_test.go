@@ -0,0 +1,118 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// equalJSON сравнивает документы без учёта порядка ключей.
+func equalJSON(t *testing.T, got []byte, want string) bool {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("result %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("want %s: %v", want, err)
+	}
+	return reflect.DeepEqual(g, w)
+}
+
+func TestMerge(t *testing.T) {
+	// Примеры из приложения A RFC 7396.
+	tests := []struct{ doc, patch, want string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		got, err := Merge([]byte(tt.doc), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("Merge(%s, %s): %v", tt.doc, tt.patch, err)
+			continue
+		}
+		if !equalJSON(t, got, tt.want) {
+			t.Errorf("Merge(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+		}
+	}
+
+	if _, err := Merge([]byte(`{}`), []byte(`{`)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Merge with malformed patch: %v, want ErrInvalid", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	const doc = `{"name":"Иван","age":30,"tags":["a","b"],"address":{"city":"Москва"}}`
+	tests := []struct {
+		name, patch, want string
+	}{
+		{"add field", `[{"op":"add","path":"/patronymic","value":"Петрович"}]`,
+			`{"name":"Иван","age":30,"patronymic":"Петрович","tags":["a","b"],"address":{"city":"Москва"}}`},
+		{"add to array", `[{"op":"add","path":"/tags/1","value":"x"},{"op":"add","path":"/tags/-","value":"z"}]`,
+			`{"name":"Иван","age":30,"tags":["a","x","b","z"],"address":{"city":"Москва"}}`},
+		{"remove", `[{"op":"remove","path":"/age"},{"op":"remove","path":"/tags/0"}]`,
+			`{"name":"Иван","tags":["b"],"address":{"city":"Москва"}}`},
+		{"replace nested", `[{"op":"replace","path":"/address/city","value":"Казань"}]`,
+			`{"name":"Иван","age":30,"tags":["a","b"],"address":{"city":"Казань"}}`},
+		{"move", `[{"op":"move","from":"/address/city","path":"/city"}]`,
+			`{"name":"Иван","age":30,"tags":["a","b"],"address":{},"city":"Москва"}`},
+		{"copy", `[{"op":"copy","from":"/tags","path":"/labels"},{"op":"add","path":"/labels/-","value":"c"}]`,
+			`{"name":"Иван","age":30,"tags":["a","b"],"labels":["a","b","c"],"address":{"city":"Москва"}}`},
+		{"test then replace", `[{"op":"test","path":"/age","value":30},{"op":"replace","path":"/age","value":31}]`,
+			`{"name":"Иван","age":31,"tags":["a","b"],"address":{"city":"Москва"}}`},
+		{"escaped pointer", `[{"op":"add","path":"/a~1b~0c","value":1}]`,
+			`{"name":"Иван","age":30,"a/b~c":1,"tags":["a","b"],"address":{"city":"Москва"}}`},
+	}
+	for _, tt := range tests {
+		got, err := Apply([]byte(doc), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !equalJSON(t, got, tt.want) {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	const doc = `{"name":"Иван","tags":["a"]}`
+	tests := []struct {
+		name, patch string
+		invalid     bool
+	}{
+		{"malformed", `{"op":"add"}`, true},
+		{"unknown op", `[{"op":"merge","path":"/name"}]`, false},
+		{"missing value", `[{"op":"add","path":"/age"}]`, false},
+		{"missing path", `[{"op":"remove","path":"/age"}]`, false},
+		{"replace missing", `[{"op":"replace","path":"/age","value":1}]`, false},
+		{"index out of range", `[{"op":"add","path":"/tags/5","value":"b"}]`, false},
+		{"leading zero index", `[{"op":"remove","path":"/tags/00"}]`, false},
+		{"relative pointer", `[{"op":"remove","path":"name"}]`, false},
+		{"move into child", `[{"op":"move","from":"/tags","path":"/tags/0"}]`, false},
+		{"failed test", `[{"op":"replace","path":"/name","value":"Пётр"},{"op":"test","path":"/name","value":"Иван"}]`, false},
+	}
+	for _, tt := range tests {
+		got, err := Apply([]byte(doc), []byte(tt.patch))
+		if err == nil {
+			t.Errorf("%s: applied as %s", tt.name, got)
+			continue
+		}
+		if errors.Is(err, ErrInvalid) != tt.invalid {
+			t.Errorf("%s: errors.Is(%v, ErrInvalid) = %t, want %t", tt.name, err, !tt.invalid, tt.invalid)
+		}
+	}
+}
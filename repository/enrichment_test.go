@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"task/models"
+	"testing"
+)
+
+func TestUpdateEnrichment(t *testing.T) {
+	sqlite, m := openTestSQLite(t)
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, r := range map[string]PersonRepository{"memory": NewMemory(), "sqlite": sqlite} {
+		t.Run(name, func(t *testing.T) {
+			p := models.Person{Name: "Иван", Surname: "Петров", EnrichmentStatus: models.EnrichmentPending,
+				Enrichment: []models.PersonEnrichment{nationality("KZ", 0.5)}}
+			if err := r.CreatePerson(&p); err != nil {
+				t.Fatal(err)
+			}
+			p.Age = 25
+			p.AgeProvenance = models.NewProvenance(models.SourceManual)
+			if err := r.UpdatePerson(p); err != nil {
+				t.Fatal(err)
+			}
+
+			saved, err := r.UpdateEnrichment(p.Id, func(person *models.Person) bool { return false })
+			if err != nil || saved {
+				t.Fatalf("declined update: saved %t, %v", saved, err)
+			}
+
+			saved, err = r.UpdateEnrichment(p.Id, func(person *models.Person) bool {
+				if person.Age != 25 || len(person.Enrichment) != 1 {
+					t.Errorf("apply got a stale record: %+v", person)
+				}
+				person.Nationality = "RU"
+				person.Enrichment = []models.PersonEnrichment{nationality("RU", 0.9)}
+				return true
+			})
+			if err != nil || !saved {
+				t.Fatalf("update: saved %t, %v", saved, err)
+			}
+			got, err := r.GetPerson(p.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Age != 25 || got.Nationality != "RU" || got.EnrichmentStatus != models.EnrichmentDone ||
+				len(got.Enrichment) != 1 || got.Enrichment[0].Value != "RU" {
+				t.Errorf("saved record = %+v", got)
+			}
+
+			if err := r.DeletePerson(p.Id); err != nil {
+				t.Fatal(err)
+			}
+			saved, err = r.UpdateEnrichment(p.Id, func(person *models.Person) bool { return true })
+			if err != nil || saved {
+				t.Errorf("deleted record: saved %t, %v", saved, err)
+			}
+		})
+	}
+}
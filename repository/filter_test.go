@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"task/models"
+	"task/rsql"
+	"testing"
+)
+
+func nationality(country string, probability float64) models.PersonEnrichment {
+	return models.PersonEnrichment{Provider: "nationalize", Field: models.FieldNationality, Value: country, Probability: probability}
+}
+
+var filterPeople = []models.Person{
+	{Name: "Иван", Surname: "Петров", Age: 30, Gender: models.Male, Nationality: "RU",
+		Enrichment: []models.PersonEnrichment{nationality("RU", 0.6), nationality("KZ", 0.3)}},
+	{Name: "Анна", Surname: "Петрова", Age: 25, Gender: models.Female, Nationality: "KZ",
+		Enrichment: []models.PersonEnrichment{nationality("KZ", 0.5), nationality("RU", 0.4)}},
+	{Name: "Иван_", Surname: "Сидоров", Patronymic: "Петрович", Age: 50, Gender: models.Male, Nationality: "UA",
+		Enrichment: []models.PersonEnrichment{nationality("UA", 0.9)}},
+	{Name: "Олег", Surname: "Иванов", Age: 41, Gender: models.Unknown},
+}
+
+func TestGetPeopleMemoryMatchesSQLite(t *testing.T) {
+	memory := NewMemory()
+	sqlite, m := openTestSQLite(t)
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []PersonRepository{memory, sqlite} {
+		people := append([]models.Person(nil), filterPeople...)
+		for i := range people {
+			people[i].Enrichment = append([]models.PersonEnrichment(nil), people[i].Enrichment...)
+		}
+		if err := r.CreatePeople(people); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	probability := func(p float64) *float64 { return &p }
+	expr := func(s string) *rsql.Expr {
+		e, err := rsql.Parse(s, PeopleFields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+
+	tests := []struct {
+		name   string
+		filter PeopleFilter
+		want   []int
+	}{
+		{"all", PeopleFilter{}, []int{1, 2, 3, 4}},
+		{"contains", PeopleFilter{Surname: "Петров"}, []int{1, 2}},
+		{"prefix", PeopleFilter{Name: "Ива", Match: MatchPrefix}, []int{1, 3}},
+		{"exact", PeopleFilter{Name: "Иван", Match: MatchExact}, []int{1}},
+		{"like escaped", PeopleFilter{Name: "н_"}, []int{3}},
+		{"not", PeopleFilter{SurnameNot: "Петров"}, []int{3, 4}},
+		{"age range", PeopleFilter{AgeMin: 26, AgeMax: 45}, []int{1, 4}},
+		{"genders", PeopleFilter{Genders: []models.Gender{models.Female, models.Unknown}}, []int{2, 4}},
+		{"nationalities", PeopleFilter{Nationalities: []string{"RU", "UA"}}, []int{1, 3}},
+		{"candidates", PeopleFilter{Nationalities: []string{"RU"}, MinProbability: probability(0.4)}, []int{1, 2}},
+		{"candidates any country", PeopleFilter{MinProbability: probability(0.55)}, []int{1, 3}},
+		{"candidates and expr", PeopleFilter{Nationalities: []string{"RU"}, MinProbability: probability(0.4), Expr: expr("gender==female")}, []int{2}},
+		{"expr", PeopleFilter{Expr: expr("age>=30 and (nationality in ('RU','UA') or gender==unknown)")}, []int{1, 3, 4}},
+		{"expr wildcard", PeopleFilter{Expr: expr("surname==Петров*;name!=Анна")}, []int{1}},
+		{"after id", PeopleFilter{AfterId: 2, Genders: []models.Gender{models.Male}}, []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, r := range map[string]PersonRepository{"memory": memory, "sqlite": sqlite} {
+				people, err := r.GetPeople(tt.filter, 100, 0)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				var ids []int
+				for _, p := range people {
+					ids = append(ids, p.Id)
+				}
+				if !reflect.DeepEqual(ids, tt.want) {
+					t.Errorf("%s: ids %v, want %v", name, ids, tt.want)
+				}
+			}
+		})
+	}
+}
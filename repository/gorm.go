@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"task/models"
+
+	"github.com/jinzhu/gorm"
+)
+
+// GormRepository - хранилище в реляционной базе через GORM (PostgreSQL или SQLite).
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// NewGorm создаёт хранилище поверх открытого соединения. Схема создаётся миграциями (см. Migrator).
+func NewGorm(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// Migrator возвращает мигратор схемы для базы хранилища.
+func (r *GormRepository) Migrator() (*Migrator, error) {
+	return NewMigrator(r.db)
+}
+
+func (r *GormRepository) GetPeople(filter PeopleFilter, limit, offset int) ([]models.Person, error) {
+	reqdb := r.db
+	if filter.Id != 0 {
+		reqdb = reqdb.Where("id = ?", filter.Id)
+	}
+	if filter.AfterId != 0 {
+		reqdb = reqdb.Where("id > ?", filter.AfterId)
+	}
+	for _, text := range []struct{ column, value, not string }{
+		{"name", filter.Name, filter.NameNot},
+		{"surname", filter.Surname, filter.SurnameNot},
+		{"patronymic", filter.Patronymic, filter.PatronymicNot},
+	} {
+		if text.value != "" {
+			reqdb = reqdb.Where(match(text.column, filter.Match, text.value))
+		}
+		if text.not != "" {
+			condition, arg := match(text.column, filter.Match, text.not)
+			reqdb = reqdb.Where("NOT ("+condition+")", arg)
+		}
+	}
+	if filter.Age != 0 {
+		reqdb = reqdb.Where("age = ?", filter.Age)
+	}
+	if filter.AgeMin != 0 {
+		reqdb = reqdb.Where("age >= ?", filter.AgeMin)
+	}
+	if filter.AgeMax != 0 {
+		reqdb = reqdb.Where("age <= ?", filter.AgeMax)
+	}
+	if len(filter.Genders) > 0 {
+		genders := make([]int, len(filter.Genders))
+		for i, g := range filter.Genders {
+			genders[i] = int(g)
+		}
+		reqdb = reqdb.Where("gender IN (?)", genders)
+	}
+	if filter.MinProbability != nil {
+		candidates := r.db.Model(&models.PersonEnrichment{}).Select("person_id").
+			Where("field = ? AND probability >= ?", models.FieldNationality, *filter.MinProbability)
+		if len(filter.Nationalities) > 0 {
+			candidates = candidates.Where("value IN (?)", filter.Nationalities)
+		}
+		reqdb = reqdb.Where("id IN (?)", candidates.QueryExpr())
+	} else if len(filter.Nationalities) > 0 {
+		reqdb = reqdb.Where("nationality IN (?)", filter.Nationalities)
+	}
+
+	if filter.Expr != nil {
+		condition, args := filter.Expr.SQL()
+		reqdb = reqdb.Where(condition, args...)
+	}
+
+	reqdb = reqdb.Preload("Enrichment", orderEnrichment).Order("id").Offset(offset).Limit(limit)
+
+	var people []models.Person
+	if err := reqdb.Find(&people).Error; err != nil {
+		return nil, err
+	}
+	return people, nil
+}
+
+// match возвращает условие сравнения столбца со значением способом mode. Символы
+// % и _ в значении экранируются, чтобы LIKE не трактовал их как шаблон.
+func match(column string, mode MatchMode, value string) (string, string) {
+	switch mode {
+	case MatchExact:
+		return column + " = ?", value
+	case MatchPrefix:
+		return column + ` LIKE ? ESCAPE '\'`, likeEscaper.Replace(value) + "%"
+	}
+	return column + ` LIKE ? ESCAPE '\'`, "%" + likeEscaper.Replace(value) + "%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// orderEnrichment сохраняет порядок ответов провайдеров, в том числе ранжирование
+// стран-кандидатов по убыванию вероятности.
+func orderEnrichment(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+func (r *GormRepository) GetPerson(id int) (models.Person, error) {
+	var person models.Person
+	if err := r.db.Preload("Enrichment", orderEnrichment).Where("id = ?", id).First(&person).Error; err != nil {
+		return models.Person{}, err
+	}
+	return person, nil
+}
+
+func (r *GormRepository) CreatePerson(person *models.Person) error {
+	if err := r.db.Create(person).Error; err != nil {
+		return fmt.Errorf("ошибка при создании пользователя: %v", err)
+	}
+	return nil
+}
+
+func (r *GormRepository) CreatePeople(people []models.Person) error {
+	tx := r.db.Begin()
+	for i := range people {
+		if err := tx.Create(&people[i]).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("ошибка при создании пользователя: %v", err)
+		}
+	}
+	return tx.Commit().Error
+}
+
+func (r *GormRepository) UpdatePerson(person models.Person) error {
+	// Обновление через map, чтобы сохранялись и нулевые значения (пустое отчество, возраст 0).
+	if err := r.db.Model(&models.Person{Id: person.Id}).Updates(map[string]any{
+		"name":        person.Name,
+		"surname":     person.Surname,
+		"patronymic":  person.Patronymic,
+		"age":         person.Age,
+		"gender":      person.Gender,
+		"nationality": person.Nationality,
+
+		"original_name":       person.Original.Name,
+		"original_surname":    person.Original.Surname,
+		"original_patronymic": person.Original.Patronymic,
+
+		"age_source":         person.AgeProvenance.Source,
+		"age_set_at":         person.AgeProvenance.SetAt,
+		"gender_source":      person.GenderProvenance.Source,
+		"gender_set_at":      person.GenderProvenance.SetAt,
+		"nationality_source": person.NationalityProvenance.Source,
+		"nationality_set_at": person.NationalityProvenance.SetAt,
+	}).Error; err != nil {
+		return fmt.Errorf("ошибка при обновлении пользователя: %v", err)
+	}
+	return nil
+}
+
+func (r *GormRepository) DeletePerson(id int) error {
+
+	if id <= 0 {
+		return fmt.Errorf("некорректный ID: %d", id)
+	}
+
+	// Ответы провайдеров удаляются явно: в SQLite внешние ключи по умолчанию не проверяются.
+	tx := r.db.Begin()
+	if err := tx.Where("person_id = ?", id).Delete(&models.PersonEnrichment{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("id = ?", id).Delete(&models.Person{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+func (r *GormRepository) GetCachedEnrichment(name string) (models.EnrichmentCache, error) {
+	var entry models.EnrichmentCache
+	if err := r.db.Where("name = ?", name).First(&entry).Error; err != nil {
+		return models.EnrichmentCache{}, err
+	}
+	return entry, nil
+}
+
+func (r *GormRepository) SaveCachedEnrichment(entry models.EnrichmentCache) error {
+	if err := r.db.Save(&entry).Error; err != nil {
+		return fmt.Errorf("ошибка при сохранении кэша обогащения: %v", err)
+	}
+	return nil
+}
+
+func (r *GormRepository) GetPendingEnrichment() ([]models.Person, error) {
+	var people []models.Person
+	if err := r.db.Where("enrichment_status = ?", models.EnrichmentPending).Find(&people).Error; err != nil {
+		return nil, err
+	}
+	return people, nil
+}
+
+// UpdateEnrichment перечитывает запись и сохраняет её после apply в одной транзакции,
+// чтобы не затереть изменения, сделанные во время обогащения. В PostgreSQL строка
+// блокируется до конца транзакции; SQLite сам не допускает параллельной записи.
+func (r *GormRepository) UpdateEnrichment(id int, apply func(person *models.Person) bool) (bool, error) {
+	tx := r.db.Begin()
+
+	query := tx
+	if tx.Dialect().GetName() == "postgres" {
+		query = tx.Set("gorm:query_option", "FOR UPDATE")
+	}
+	var person models.Person
+	err := query.Where("id = ?", id).First(&person).Error
+	if err == nil {
+		err = orderEnrichment(tx.Where("person_id = ?", id)).Find(&person.Enrichment).Error
+	}
+	if errors.Is(err, ErrNotFound) {
+		tx.Rollback()
+		return false, nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if !apply(&person) {
+		tx.Rollback()
+		return false, nil
+	}
+	if err := saveEnrichment(tx, person); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// saveEnrichment сохраняет вычисленные поля и заменяет ответы провайдеров в транзакции tx.
+func saveEnrichment(tx *gorm.DB, person models.Person) error {
+	err := tx.Model(&models.Person{Id: person.Id}).Updates(map[string]any{
+		"age":               person.Age,
+		"gender":            person.Gender,
+		"nationality":       person.Nationality,
+		"enrichment_status": models.EnrichmentDone,
+		"enrichment_error":  "",
+
+		"age_source":         person.AgeProvenance.Source,
+		"age_set_at":         person.AgeProvenance.SetAt,
+		"gender_source":      person.GenderProvenance.Source,
+		"gender_set_at":      person.GenderProvenance.SetAt,
+		"nationality_source": person.NationalityProvenance.Source,
+		"nationality_set_at": person.NationalityProvenance.SetAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("ошибка при сохранении обогащения: %v", err)
+	}
+
+	if err := tx.Where("person_id = ?", person.Id).Delete(&models.PersonEnrichment{}).Error; err != nil {
+		return fmt.Errorf("ошибка при сохранении обогащения: %v", err)
+	}
+	for _, e := range person.Enrichment {
+		e.PersonId = person.Id
+		if err := tx.Create(&e).Error; err != nil {
+			return fmt.Errorf("ошибка при сохранении обогащения: %v", err)
+		}
+	}
+	return nil
+}
+
+func (r *GormRepository) SetEnrichmentStatus(id int, status, errMsg string) error {
+	err := r.db.Model(&models.Person{Id: id}).Updates(map[string]any{
+		"enrichment_status": status,
+		"enrichment_error":  errMsg,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("ошибка при обновлении статуса обогащения: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"task/models"
+)
+
+// Memory - хранилище в памяти процесса для тестов обработчиков и запуска без базы.
+// Фильтры GetPeople работают так же, как в GormRepository.
+type Memory struct {
+	mu           sync.RWMutex
+	people       map[int]models.Person
+	cache        map[string]models.EnrichmentCache
+	nextID       int
+	nextDetailID int
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		people: map[int]models.Person{},
+		cache:  map[string]models.EnrichmentCache{},
+	}
+}
+
+// clone возвращает копию записи, не разделяющую с хранилищем ответы провайдеров.
+func clone(p models.Person) models.Person {
+	p.Enrichment = append([]models.PersonEnrichment(nil), p.Enrichment...)
+	return p
+}
+
+func (m *Memory) GetPeople(filter PeopleFilter, limit, offset int) ([]models.Person, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var people []models.Person
+	for _, p := range m.people {
+		if filter.matches(p) {
+			people = append(people, clone(p))
+		}
+	}
+	sort.Slice(people, func(i, j int) bool { return people[i].Id < people[j].Id })
+
+	if offset > len(people) {
+		offset = len(people)
+	}
+	people = people[offset:]
+	if limit >= 0 && limit < len(people) {
+		people = people[:limit]
+	}
+	return people, nil
+}
+
+func (f PeopleFilter) matches(p models.Person) bool {
+	if f.Id != 0 && p.Id != f.Id {
+		return false
+	}
+	if p.Id <= f.AfterId {
+		return false
+	}
+	for _, text := range []struct{ field, value, not string }{
+		{p.Name, f.Name, f.NameNot},
+		{p.Surname, f.Surname, f.SurnameNot},
+		{p.Patronymic, f.Patronymic, f.PatronymicNot},
+	} {
+		if text.value != "" && !f.Match.matches(text.field, text.value) {
+			return false
+		}
+		if text.not != "" && f.Match.matches(text.field, text.not) {
+			return false
+		}
+	}
+	if f.Age != 0 && p.Age != f.Age {
+		return false
+	}
+	if f.AgeMin != 0 && p.Age < f.AgeMin {
+		return false
+	}
+	if f.AgeMax != 0 && p.Age > f.AgeMax {
+		return false
+	}
+	if len(f.Genders) > 0 && !slices.Contains(f.Genders, p.Gender) {
+		return false
+	}
+	if f.MinProbability != nil {
+		candidate := false
+		for _, e := range p.Enrichment {
+			if e.Field == models.FieldNationality && e.Probability >= *f.MinProbability &&
+				(len(f.Nationalities) == 0 || slices.Contains(f.Nationalities, e.Value)) {
+				candidate = true
+				break
+			}
+		}
+		if !candidate {
+			return false
+		}
+	} else if len(f.Nationalities) > 0 && !slices.Contains(f.Nationalities, p.Nationality) {
+		return false
+	}
+	if f.Expr != nil && !f.Expr.Match(func(field string) any { return personField(p, field) }) {
+		return false
+	}
+	return true
+}
+
+// personField возвращает значение поля из PeopleFields в том виде, в каком оно хранится в базе.
+func personField(p models.Person, field string) any {
+	switch field {
+	case "id":
+		return p.Id
+	case "name":
+		return p.Name
+	case "surname":
+		return p.Surname
+	case "patronymic":
+		return p.Patronymic
+	case "age":
+		return p.Age
+	case "gender":
+		return int(p.Gender)
+	case "nationality":
+		return p.Nationality
+	}
+	panic("repository: поле " + field + " отсутствует в PeopleFields")
+}
+
+func (m MatchMode) matches(s, value string) bool {
+	switch m {
+	case MatchExact:
+		return s == value
+	case MatchPrefix:
+		return strings.HasPrefix(s, value)
+	}
+	return strings.Contains(s, value)
+}
+
+func (m *Memory) GetPerson(id int) (models.Person, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.people[id]
+	if !ok {
+		return models.Person{}, ErrNotFound
+	}
+	return clone(p), nil
+}
+
+// insert сохраняет новую запись, назначая ID ей и ответам провайдеров. Вызывается под блокировкой.
+func (m *Memory) insert(p *models.Person) {
+	m.nextID++
+	p.Id = m.nextID
+	if p.EnrichmentStatus == "" {
+		p.EnrichmentStatus = models.EnrichmentDone
+	}
+	m.setEnrichment(p, p.Enrichment)
+	m.people[p.Id] = clone(*p)
+}
+
+func (m *Memory) setEnrichment(p *models.Person, details []models.PersonEnrichment) {
+	p.Enrichment = make([]models.PersonEnrichment, len(details))
+	for i, e := range details {
+		m.nextDetailID++
+		e.Id = m.nextDetailID
+		e.PersonId = p.Id
+		p.Enrichment[i] = e
+	}
+}
+
+func (m *Memory) CreatePerson(person *models.Person) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.insert(person)
+	return nil
+}
+
+func (m *Memory) CreatePeople(people []models.Person) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range people {
+		m.insert(&people[i])
+	}
+	return nil
+}
+
+func (m *Memory) UpdatePerson(person models.Person) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.people[person.Id]
+	if !ok {
+		return nil
+	}
+	p.Name = person.Name
+	p.Surname = person.Surname
+	p.Patronymic = person.Patronymic
+	p.Age = person.Age
+	p.Gender = person.Gender
+	p.Nationality = person.Nationality
+	p.Original = person.Original
+	p.AgeProvenance = person.AgeProvenance
+	p.GenderProvenance = person.GenderProvenance
+	p.NationalityProvenance = person.NationalityProvenance
+	m.people[p.Id] = p
+	return nil
+}
+
+func (m *Memory) DeletePerson(id int) error {
+	if id <= 0 {
+		return fmt.Errorf("некорректный ID: %d", id)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.people, id)
+	return nil
+}
+
+func (m *Memory) GetPendingEnrichment() ([]models.Person, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var people []models.Person
+	for _, p := range m.people {
+		if p.EnrichmentStatus == models.EnrichmentPending {
+			people = append(people, clone(p))
+		}
+	}
+	sort.Slice(people, func(i, j int) bool { return people[i].Id < people[j].Id })
+	return people, nil
+}
+
+func (m *Memory) UpdateEnrichment(id int, apply func(person *models.Person) bool) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.people[id]
+	if !ok {
+		return false, nil
+	}
+	person := clone(p)
+	if !apply(&person) {
+		return false, nil
+	}
+	m.saveEnrichment(p, person)
+	return true, nil
+}
+
+// saveEnrichment переносит в p вычисленные поля person. Вызывается под блокировкой.
+func (m *Memory) saveEnrichment(p, person models.Person) {
+	p.Age = person.Age
+	p.Gender = person.Gender
+	p.Nationality = person.Nationality
+	p.EnrichmentStatus = models.EnrichmentDone
+	p.EnrichmentError = ""
+	p.AgeProvenance = person.AgeProvenance
+	p.GenderProvenance = person.GenderProvenance
+	p.NationalityProvenance = person.NationalityProvenance
+	m.setEnrichment(&p, person.Enrichment)
+	m.people[p.Id] = p
+}
+
+func (m *Memory) SetEnrichmentStatus(id int, status, errMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if p, ok := m.people[id]; ok {
+		p.EnrichmentStatus = status
+		p.EnrichmentError = errMsg
+		m.people[id] = p
+	}
+	return nil
+}
+
+func (m *Memory) GetCachedEnrichment(name string) (models.EnrichmentCache, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.cache[name]
+	if !ok {
+		return models.EnrichmentCache{}, ErrNotFound
+	}
+	return entry, nil
+}
+
+func (m *Memory) SaveCachedEnrichment(entry models.EnrichmentCache) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cache[entry.Name] = entry
+	return nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"task/models"
+	"testing"
+)
+
+// baselinePerson - модель people до появления миграций, схему которой создавал AutoMigrate.
+type baselinePerson struct {
+	Id          int
+	Name        string        `gorm:"type:varchar(100);not null"`
+	Surname     string        `gorm:"type:varchar(100);not null"`
+	Patronymic  string        `gorm:"type:varchar(100)"`
+	Age         int           `gorm:"default:0"`
+	Gender      models.Gender `gorm:"type:integer"`
+	Nationality string        `gorm:"type:varchar(50)"`
+}
+
+func (baselinePerson) TableName() string { return "people" }
+
+func openTestSQLite(t *testing.T) (*GormRepository, *Migrator) {
+	t.Helper()
+	r, err := OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.db.Close() })
+	m, err := r.Migrator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, m
+}
+
+func columns(t *testing.T, r *GormRepository, table string) []string {
+	t.Helper()
+	rows, err := r.db.DB().Query("SELECT name FROM pragma_table_info('" + table + "')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestMigrateMatchesModels(t *testing.T) {
+	r, m := openTestSQLite(t)
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	auto, _ := openTestSQLite(t)
+	auto.db.AutoMigrate(&models.Person{}, &models.PersonEnrichment{}, &models.EnrichmentCache{})
+
+	for _, table := range []string{"people", "person_enrichment", "enrichment_cache"} {
+		if got, want := columns(t, r, table), columns(t, auto, table); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: columns %v, models expect %v", table, got, want)
+		}
+	}
+}
+
+func TestMigrateAdoptsBaselineDatabase(t *testing.T) {
+	r, m := openTestSQLite(t)
+	ctx := context.Background()
+	r.db.AutoMigrate(&baselinePerson{})
+	if err := r.db.Create(&baselinePerson{Name: "Иван", Surname: "Петров", Age: 30}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != len(m.migrations) {
+		t.Fatalf("applied %d migrations, want %d", len(done), len(m.migrations))
+	}
+
+	old, err := r.GetPerson(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.Name != "Иван" || old.Age != 30 || old.EnrichmentStatus != models.EnrichmentDone {
+		t.Errorf("existing row after migration: %+v", old)
+	}
+
+	p := models.Person{
+		Name:       "Анна",
+		Surname:    "Петрова",
+		Original:   models.FullName{Name: "anna", Surname: "petrova"},
+		Enrichment: []models.PersonEnrichment{{Provider: "agify", Field: models.FieldAge, Value: "25"}},
+	}
+	if err := r.CreatePerson(&p); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SaveCachedEnrichment(models.EnrichmentCache{Name: "Анна"}); err != nil {
+		t.Fatal(err)
+	}
+	var keyType string
+	if err := r.db.DB().QueryRow("SELECT type FROM pragma_table_info('enrichment_cache') WHERE name = 'name'").Scan(&keyType); err != nil || keyType != "text" {
+		t.Errorf("enrichment_cache.name type = %q, %v, want text", keyType, err)
+	}
+
+	if done, _ := m.Up(ctx); len(done) != 0 {
+		t.Errorf("second Up applied %d migrations", len(done))
+	}
+}
+
+func TestMigrateDownAndUp(t *testing.T) {
+	r, m := openTestSQLite(t)
+	ctx := context.Background()
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CreatePerson(&models.Person{Name: "Иван", Surname: "Петров", Age: 30}); err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := m.Down(ctx, 2)
+	if err != nil || len(done) != 2 || done[0].Version != 3 || done[1].Version != 2 {
+		t.Fatalf("Down(2) = %v, %v", done, err)
+	}
+	want := []string{"age", "gender", "id", "name", "nationality", "patronymic", "surname"}
+	if got := columns(t, r, "people"); !reflect.DeepEqual(got, want) {
+		t.Errorf("columns after down: %v, want %v", got, want)
+	}
+
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status[0].AppliedAt == nil || status[1].AppliedAt != nil {
+		t.Errorf("status after down: %+v", status)
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	p, err := r.GetPerson(1)
+	if err != nil || p.Name != "Иван" || p.Age != 30 {
+		t.Errorf("row after down and up: %+v, %v", p, err)
+	}
+}
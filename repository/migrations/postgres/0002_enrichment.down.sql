@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS enrichment_cache;
+DROP TABLE IF EXISTS person_enrichment;
+
+ALTER TABLE people
+    DROP COLUMN IF EXISTS original_name,
+    DROP COLUMN IF EXISTS original_surname,
+    DROP COLUMN IF EXISTS original_patronymic,
+    DROP COLUMN IF EXISTS age_source,
+    DROP COLUMN IF EXISTS age_set_at,
+    DROP COLUMN IF EXISTS gender_source,
+    DROP COLUMN IF EXISTS gender_set_at,
+    DROP COLUMN IF EXISTS nationality_source,
+    DROP COLUMN IF EXISTS nationality_set_at,
+    DROP COLUMN IF EXISTS enrichment_status,
+    DROP COLUMN IF EXISTS enrichment_error;
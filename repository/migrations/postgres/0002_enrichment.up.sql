@@ -0,0 +1,37 @@
+-- Обогащение: исходные имена, происхождение значений, статус асинхронного обогащения,
+-- ответы провайдеров и кэш. IF NOT EXISTS - для баз, обновлённых ранее через AutoMigrate.
+ALTER TABLE people
+    ADD COLUMN IF NOT EXISTS original_name       text,
+    ADD COLUMN IF NOT EXISTS original_surname    text,
+    ADD COLUMN IF NOT EXISTS original_patronymic text,
+    ADD COLUMN IF NOT EXISTS age_source          varchar(50),
+    ADD COLUMN IF NOT EXISTS age_set_at          timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS gender_source       varchar(50),
+    ADD COLUMN IF NOT EXISTS gender_set_at       timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS nationality_source  varchar(50),
+    ADD COLUMN IF NOT EXISTS nationality_set_at  timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS enrichment_status   varchar(20) DEFAULT 'done',
+    ADD COLUMN IF NOT EXISTS enrichment_error    text;
+
+CREATE TABLE IF NOT EXISTS person_enrichment (
+    id          serial PRIMARY KEY,
+    person_id   integer NOT NULL REFERENCES people (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    provider    varchar(50) NOT NULL,
+    field       varchar(20) NOT NULL,
+    value       varchar(100),
+    probability numeric,
+    count       integer,
+    fetched_at  timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_person_enrichment_person_id ON person_enrichment (person_id);
+CREATE INDEX IF NOT EXISTS idx_person_enrichment_field_value ON person_enrichment (field, value);
+
+CREATE TABLE IF NOT EXISTS enrichment_cache (
+    name        varchar(100) PRIMARY KEY,
+    age         integer DEFAULT 0,
+    gender      integer,
+    nationality varchar(50),
+    details     text,
+    fetched_at  timestamp with time zone NOT NULL
+);
@@ -0,0 +1,2 @@
+DELETE FROM enrichment_cache WHERE length(name) > 100;
+ALTER TABLE enrichment_cache ALTER COLUMN name TYPE varchar(100);
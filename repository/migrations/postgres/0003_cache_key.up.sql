@@ -0,0 +1,3 @@
+-- Ключ кэша обогащения - имя в нижнем регистре и код страны ("иван|RU"), он длиннее
+-- поля name (до 100 символов).
+ALTER TABLE enrichment_cache ALTER COLUMN name TYPE text;
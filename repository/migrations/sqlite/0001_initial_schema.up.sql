@@ -0,0 +1,13 @@
+-- Исходная схема таблицы people; новые столбцы добавляют следующие миграции.
+CREATE TABLE IF NOT EXISTS people (
+    id          integer PRIMARY KEY AUTOINCREMENT,
+    name        varchar(100) NOT NULL,
+    surname     varchar(100) NOT NULL,
+    patronymic  varchar(100),
+    age         integer DEFAULT 0,
+    gender      integer,
+    nationality varchar(50)
+);
+
+CREATE INDEX IF NOT EXISTS idx_person_name ON people (name);
+CREATE INDEX IF NOT EXISTS idx_person_surname ON people (surname);
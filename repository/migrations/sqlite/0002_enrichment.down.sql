@@ -0,0 +1,20 @@
+DROP TABLE IF EXISTS enrichment_cache;
+DROP TABLE IF EXISTS person_enrichment;
+
+-- SQLite до 3.35 не поддерживает DROP COLUMN, поэтому таблица пересоздаётся.
+CREATE TABLE people_0001 (
+    id          integer PRIMARY KEY AUTOINCREMENT,
+    name        varchar(100) NOT NULL,
+    surname     varchar(100) NOT NULL,
+    patronymic  varchar(100),
+    age         integer DEFAULT 0,
+    gender      integer,
+    nationality varchar(50)
+);
+INSERT INTO people_0001 (id, name, surname, patronymic, age, gender, nationality)
+    SELECT id, name, surname, patronymic, age, gender, nationality FROM people;
+DROP TABLE people;
+ALTER TABLE people_0001 RENAME TO people;
+
+CREATE INDEX idx_person_name ON people (name);
+CREATE INDEX idx_person_surname ON people (surname);
@@ -0,0 +1,36 @@
+-- Обогащение: исходные имена, происхождение значений, статус асинхронного обогащения,
+-- ответы провайдеров и кэш.
+ALTER TABLE people ADD COLUMN original_name text;
+ALTER TABLE people ADD COLUMN original_surname text;
+ALTER TABLE people ADD COLUMN original_patronymic text;
+ALTER TABLE people ADD COLUMN age_source varchar(50);
+ALTER TABLE people ADD COLUMN age_set_at datetime;
+ALTER TABLE people ADD COLUMN gender_source varchar(50);
+ALTER TABLE people ADD COLUMN gender_set_at datetime;
+ALTER TABLE people ADD COLUMN nationality_source varchar(50);
+ALTER TABLE people ADD COLUMN nationality_set_at datetime;
+ALTER TABLE people ADD COLUMN enrichment_status varchar(20) DEFAULT 'done';
+ALTER TABLE people ADD COLUMN enrichment_error text;
+
+CREATE TABLE IF NOT EXISTS person_enrichment (
+    id          integer PRIMARY KEY AUTOINCREMENT,
+    person_id   integer NOT NULL REFERENCES people (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    provider    varchar(50) NOT NULL,
+    field       varchar(20) NOT NULL,
+    value       varchar(100),
+    probability real,
+    count       integer,
+    fetched_at  datetime
+);
+
+CREATE INDEX IF NOT EXISTS idx_person_enrichment_person_id ON person_enrichment (person_id);
+CREATE INDEX IF NOT EXISTS idx_person_enrichment_field_value ON person_enrichment (field, value);
+
+CREATE TABLE IF NOT EXISTS enrichment_cache (
+    name        varchar(100) PRIMARY KEY,
+    age         integer DEFAULT 0,
+    gender      integer,
+    nationality varchar(50),
+    details     text,
+    fetched_at  datetime NOT NULL
+);
@@ -0,0 +1,10 @@
+DROP TABLE enrichment_cache;
+
+CREATE TABLE enrichment_cache (
+    name        varchar(100) PRIMARY KEY,
+    age         integer DEFAULT 0,
+    gender      integer,
+    nationality varchar(50),
+    details     text,
+    fetched_at  datetime NOT NULL
+);
@@ -0,0 +1,13 @@
+-- Ключ кэша обогащения - имя в нижнем регистре и код страны ("иван|RU"), он длиннее
+-- поля name (до 100 символов). SQLite не изменяет тип столбца, поэтому таблица кэша
+-- создаётся заново: кэш заполнится повторно.
+DROP TABLE enrichment_cache;
+
+CREATE TABLE enrichment_cache (
+    name        text PRIMARY KEY,
+    age         integer DEFAULT 0,
+    gender      integer,
+    nationality varchar(50),
+    details     text,
+    fetched_at  datetime NOT NULL
+);
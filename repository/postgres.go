@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+)
+
+// OpenPostgres подключается к PostgreSQL по переменным DB_USER, DB_PASSWORD, DB_NAME,
+// DB_HOST и DB_PORT.
+func OpenPostgres() (*GormRepository, error) {
+	user := os.Getenv("DB_USER")
+	password := os.Getenv("DB_PASSWORD")
+	dbname := os.Getenv("DB_NAME")
+	host := os.Getenv("DB_HOST")
+	port := os.Getenv("DB_PORT")
+
+	connectionString := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable", user, password, dbname, host, port)
+
+	db, err := gorm.Open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+	return NewGorm(db), nil
+}
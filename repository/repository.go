@@ -1,115 +1,223 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"task/config"
 	"task/models"
+	"task/rsql"
 
 	"github.com/jinzhu/gorm"
-	_ "github.com/jinzhu/gorm/dialects/postgres"
 )
 
-var db *gorm.DB
+// PersonRepository - хранилище людей, результатов их обогащения и кэша обогащения.
+type PersonRepository interface {
+	GetPeople(filter PeopleFilter, limit, offset int) ([]models.Person, error)
+	GetPerson(id int) (models.Person, error)
+	CreatePerson(person *models.Person) error
+	CreatePeople(people []models.Person) error
+	UpdatePerson(person models.Person) error
+	DeletePerson(id int) error
+
+	GetPendingEnrichment() ([]models.Person, error)
+	UpdateEnrichment(id int, apply func(person *models.Person) bool) (bool, error)
+	SetEnrichmentStatus(id int, status, errMsg string) error
+
+	GetCachedEnrichment(name string) (models.EnrichmentCache, error)
+	SaveCachedEnrichment(entry models.EnrichmentCache) error
+}
 
-func LoadDB() {
-	var err error
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
+// ErrNotFound возвращается, если запись не найдена.
+var ErrNotFound = gorm.ErrRecordNotFound
 
-	connectionString := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable", user, password, dbname, host, port)
+var repo PersonRepository
+
+// Use устанавливает хранилище, с которым работают функции пакета.
+func Use(r PersonRepository) {
+	repo = r
+}
 
-	db, err = gorm.Open("postgres", connectionString)
+// LoadDB подключает хранилище, выбранное переменной DB_DRIVER: postgres (по умолчанию),
+// sqlite (файл DB_PATH) или memory (в памяти процесса, данные теряются при перезапуске).
+// Для баз данных применяются миграции схемы, если DB_AUTO_MIGRATE не равно false;
+// иначе запуск прерывается, пока есть неприменённые миграции.
+func LoadDB() {
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = config.DBDriver
+	}
+	if driver == "memory" {
+		Use(NewMemory())
+		config.Logger.Warn("Используется хранилище в памяти, данные не сохраняются между запусками")
+		return
+	}
+
+	r, err := Open(driver)
 	if err != nil {
 		config.Logger.Fatal("Ошибка при подключении к базе данных: ", err)
 	}
+	config.Logger.Debug("Успешно подключено к базе данных: ", driver)
 
-	db.AutoMigrate(&models.Person{}).
-		AddIndex("idx_person_id", "id").
-		AddIndex("idx_person_name", "name").
-		AddIndex("idx_person_surname", "surname")
-
-	config.Logger.Debug("Успешно подключено к базе данных PostgreSQL")
-}
-
-func GetPeople(idStr, name, surname, patronymic, ageStr, gender, nationality string, limit, offset int) ([]models.Person, error) {
-	reqdb := db
-	if idStr != "" {
-		id, err := strconv.Atoi(idStr)
-		if err == nil {
-			if id <= 0 {
-				return nil, fmt.Errorf("некорректный ID: %d", id)
-			}
-			reqdb = reqdb.Where("id = ?", id)
-		}
-	}
-	if name != "" {
-		reqdb = reqdb.Where("name LIKE ?", "%"+name+"%")
+	m, err := r.Migrator()
+	if err != nil {
+		config.Logger.Fatal("Ошибка при загрузке миграций: ", err)
 	}
-	if surname != "" {
-		reqdb = reqdb.Where("surname LIKE ?", "%"+surname+"%")
+	autoMigrate := config.DBAutoMigrate
+	if v, err := strconv.ParseBool(os.Getenv("DB_AUTO_MIGRATE")); err == nil {
+		autoMigrate = v
 	}
-	if ageStr != "" {
-		age, err := strconv.Atoi(ageStr)
-		if err == nil {
-			if age <= 0 {
-				return nil, fmt.Errorf("некорректный возраст: %d", age)
+	if autoMigrate {
+		done, err := m.Up(context.Background())
+		if err != nil {
+			config.Logger.Fatal("Ошибка при миграции базы данных: ", err)
+		}
+		for _, mg := range done {
+			config.Logger.Infof("Применена миграция %d_%s", mg.Version, mg.Name)
+		}
+	} else {
+		status, err := m.Status(context.Background())
+		if err != nil {
+			config.Logger.Fatal("Ошибка при проверке миграций: ", err)
+		}
+		for _, s := range status {
+			if s.AppliedAt == nil {
+				config.Logger.Fatalf("Не применена миграция %d_%s, выполните migrate up", s.Version, s.Name)
 			}
-			reqdb = reqdb.Where("age = ?", age)
 		}
 	}
-	if gender != "" {
-		reqdb = reqdb.Where("gender LIKE ?", "%"+gender+"%")
-	}
-	if nationality != "" {
-		reqdb = reqdb.Where("nationality LIKE ?", "%"+nationality+"%")
+	Use(r)
+}
+
+// Open подключается к базе данных драйвера postgres или sqlite без применения миграций.
+func Open(driver string) (*GormRepository, error) {
+	switch driver {
+	case "postgres":
+		return OpenPostgres()
+	case "sqlite":
+		path := os.Getenv("DB_PATH")
+		if path == "" {
+			path = config.DBPath
+		}
+		return OpenSQLite(path)
 	}
+	return nil, fmt.Errorf("неизвестный драйвер базы данных: %s", driver)
+}
 
-	reqdb = reqdb.Offset(offset).Limit(limit)
+// MatchMode - способ сравнения строковых фильтров PeopleFilter.
+type MatchMode string
 
-	var people []models.Person
-	err := reqdb.Find(&people).Error
-	if err != nil {
-		return nil, err
-	}
+const (
+	MatchContains MatchMode = "contains"
+	MatchPrefix   MatchMode = "prefix"
+	MatchExact    MatchMode = "exact"
+)
 
-	return people, nil
+// PeopleFilter - фильтры поиска людей. Пустые поля не ограничивают выборку.
+// Строковые поля сравниваются способом Match (по умолчанию - подстрока), поля *Not
+// исключают совпадения, списки Genders и Nationalities задают допустимые значения.
+type PeopleFilter struct {
+	Id            int
+	Name          string
+	Surname       string
+	Patronymic    string
+	NameNot       string
+	SurnameNot    string
+	PatronymicNot string
+	Match         MatchMode
+	Age           int
+	AgeMin        int
+	AgeMax        int
+	Genders       []models.Gender
+	Nationalities []string
+	// MinProbability переключает поиск Nationalities на всех стран-кандидатов
+	// провайдеров с вероятностью не ниже указанной.
+	MinProbability *float64
+	// Expr - выражение фильтрации по полям PeopleFields, дополняющее остальные фильтры.
+	Expr *rsql.Expr
+	// AfterId оставляет только записи с ID больше указанного (постраничный обход по ключу).
+	AfterId int
 }
 
-func CreatePerson(person models.Person) error {
-	if err := db.Create(&person).Error; err != nil {
-		return fmt.Errorf("ошибка при создании пользователя: %v", err)
+// PeopleFields - поля людей, доступные в выражениях фильтрации.
+var PeopleFields = rsql.Fields{
+	"id":          {Column: "id", Kind: rsql.Int},
+	"name":        {Column: "name", Kind: rsql.String},
+	"surname":     {Column: "surname", Kind: rsql.String},
+	"patronymic":  {Column: "patronymic", Kind: rsql.String},
+	"age":         {Column: "age", Kind: rsql.Int},
+	"gender":      {Column: "gender", Kind: rsql.Enum, Value: parseGender},
+	"nationality": {Column: "nationality", Kind: rsql.Enum, Value: parseCountry},
+}
+
+func parseGender(s string) (any, error) {
+	g, ok := models.ParseGender(strings.ToLower(s))
+	if !ok {
+		return nil, fmt.Errorf("must be one of male, female, unknown or 0, 1, 2")
 	}
-	return nil
+	return int(g), nil
 }
 
-func UpdatePerson(person models.Person) error {
-	if err := db.Model(&person).Updates(models.Person{
-		Name:        person.Name,
-		Surname:     person.Surname,
-		Patronymic:  person.Patronymic,
-		Age:         person.Age,
-		Gender:      person.Gender,
-		Nationality: person.Nationality,
-	}).Error; err != nil {
-		return fmt.Errorf("ошибка при обновлении пользователя: %v", err)
+func parseCountry(s string) (any, error) {
+	s = strings.ToUpper(s)
+	if len(s) != 2 || s[0] < 'A' || s[0] > 'Z' || s[1] < 'A' || s[1] > 'Z' {
+		return nil, fmt.Errorf("must be an ISO 3166-1 alpha-2 code")
 	}
-	return nil
+	return s, nil
+}
+
+// GetPeople ищет людей по фильтрам. Если задан MinProbability, Nationalities ищутся
+// среди всех стран-кандидатов провайдеров с вероятностью не ниже указанной,
+// иначе - по итоговой национальности.
+func GetPeople(filter PeopleFilter, limit, offset int) ([]models.Person, error) {
+	return repo.GetPeople(filter, limit, offset)
+}
+
+func GetPerson(id int) (models.Person, error) {
+	return repo.GetPerson(id)
+}
+
+func CreatePerson(person *models.Person) error {
+	return repo.CreatePerson(person)
+}
+
+func CreatePeople(people []models.Person) error {
+	return repo.CreatePeople(people)
+}
+
+func UpdatePerson(person models.Person) error {
+	return repo.UpdatePerson(person)
 }
 
 func DeletePerson(id int) error {
+	return repo.DeletePerson(id)
+}
 
-	if id <= 0 {
-		return fmt.Errorf("некорректный ID: %d", id)
-	}
+// EnrichmentCacheStore хранит кэш обогащения в текущем хранилище.
+type EnrichmentCacheStore struct{}
 
-	err := db.Where("id = ?", id).Delete(&models.Person{}).Error
-	if err != nil {
-		return err
-	}
-	return nil
+func (EnrichmentCacheStore) GetCachedEnrichment(name string) (models.EnrichmentCache, error) {
+	return repo.GetCachedEnrichment(name)
+}
+
+func (EnrichmentCacheStore) SaveCachedEnrichment(entry models.EnrichmentCache) error {
+	return repo.SaveCachedEnrichment(entry)
+}
+
+// GetPendingEnrichment возвращает людей, ожидающих асинхронного обогащения.
+func GetPendingEnrichment() ([]models.Person, error) {
+	return repo.GetPendingEnrichment()
+}
+
+// UpdateEnrichment атомарно перечитывает запись, передаёт её в apply и сохраняет
+// вычисленные поля и ответы провайдеров. false означает, что запись удалена или
+// apply отказался от сохранения.
+func UpdateEnrichment(id int, apply func(person *models.Person) bool) (bool, error) {
+	return repo.UpdateEnrichment(id, apply)
+}
+
+func SetEnrichmentStatus(id int, status, errMsg string) error {
+	return repo.SetEnrichmentStatus(id, status, errMsg)
 }
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+// OpenSQLite открывает файл базы SQLite для локальной разработки. Путь ":memory:"
+// создаёт базу в памяти.
+func OpenSQLite(path string) (*GormRepository, error) {
+	db, err := gorm.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	// Одно соединение: база ":memory:" существует только в рамках соединения,
+	// а SQLite всё равно не допускает параллельной записи.
+	db.DB().SetMaxOpenConns(1)
+	return NewGorm(db), nil
+}
@@ -0,0 +1,576 @@
+// Package rsql разбирает выражения фильтрации в стиле RSQL/FIQL, например
+// age>=30 and (nationality in ("RU","KZ") or gender=female), и переводит их
+// в параметризованное условие SQL или проверяет на значениях записи.
+package rsql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ограничения сложности выражения.
+const (
+	MaxLength = 2000 // символов в выражении
+	MaxTerms  = 32   // сравнений
+	MaxDepth  = 16   // вложенных скобок и отрицаний
+	MaxValues = 100  // значений в списке in
+)
+
+// Kind - тип поля, определяющий допустимые операторы и разбор значений.
+type Kind int
+
+const (
+	// String сравнивается на равенство; символ * в значении означает любую подстроку.
+	String Kind = iota
+	// Int допускает сравнения на больше и меньше.
+	Int
+	// Enum сравнивается только на равенство со значениями, которые возвращает Field.Value.
+	Enum
+)
+
+// Field - поле, доступное в выражении.
+type Field struct {
+	Column string
+	Kind   Kind
+	// Value разбирает значение из выражения. Для Enum обязательно, для String и Int
+	// заменяет разбор по умолчанию.
+	Value func(s string) (any, error)
+}
+
+// Fields - белый список полей выражения по именам.
+type Fields map[string]Field
+
+func (f Fields) names() string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Error - ошибка разбора с позицией (в символах, с единицы) в исходном выражении.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+// Expr - разобранное выражение.
+type Expr struct {
+	root node
+}
+
+// Parse разбирает выражение s, допуская только поля из fields.
+func Parse(s string, fields Fields) (*Expr, error) {
+	if utf8.RuneCountInString(s) > MaxLength {
+		return nil, &Error{Pos: 1, Msg: fmt.Sprintf("expression is too long: more than %d characters", MaxLength)}
+	}
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: s, tokens: tokens, fields: fields}
+	if p.peek().kind == tEOF {
+		return nil, p.errorf(p.peek(), "empty expression")
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return &Expr{root: root}, nil
+}
+
+// SQL возвращает условие WHERE с плейсхолдерами ? и значения для них. Имена
+// столбцов берутся только из белого списка, значения передаются параметрами.
+func (e *Expr) SQL() (string, []any) {
+	var b strings.Builder
+	var args []any
+	e.root.sql(&b, &args)
+	return b.String(), args
+}
+
+// Match проверяет выражение на записи; get возвращает значение поля по имени
+// (string для String, int для Int, для Enum - того же типа, что Field.Value).
+func (e *Expr) Match(get func(field string) any) bool {
+	return e.root.match(get)
+}
+
+type operator int
+
+const (
+	opEq operator = iota
+	opNe
+	opLt
+	opLe
+	opGt
+	opGe
+	opIn
+	opOut
+)
+
+var operators = map[string]operator{
+	"=": opEq, "==": opEq, "=eq=": opEq,
+	"!=": opNe, "=ne=": opNe,
+	"<": opLt, "=lt=": opLt,
+	"<=": opLe, "=le=": opLe,
+	">": opGt, "=gt=": opGt,
+	">=": opGe, "=ge=": opGe,
+	"=in=":  opIn,
+	"=out=": opOut,
+}
+
+var sqlOperators = map[operator]string{opEq: "=", opNe: "<>", opLt: "<", opLe: "<=", opGt: ">", opGe: ">="}
+
+type node interface {
+	sql(b *strings.Builder, args *[]any)
+	match(get func(field string) any) bool
+}
+
+type logical struct {
+	and         bool
+	left, right node
+}
+
+func (n logical) sql(b *strings.Builder, args *[]any) {
+	b.WriteString("(")
+	n.left.sql(b, args)
+	if n.and {
+		b.WriteString(" AND ")
+	} else {
+		b.WriteString(" OR ")
+	}
+	n.right.sql(b, args)
+	b.WriteString(")")
+}
+
+func (n logical) match(get func(string) any) bool {
+	if n.and {
+		return n.left.match(get) && n.right.match(get)
+	}
+	return n.left.match(get) || n.right.match(get)
+}
+
+type negation struct {
+	x node
+}
+
+func (n negation) sql(b *strings.Builder, args *[]any) {
+	b.WriteString("NOT (")
+	n.x.sql(b, args)
+	b.WriteString(")")
+}
+
+func (n negation) match(get func(string) any) bool {
+	return !n.x.match(get)
+}
+
+type comparison struct {
+	name   string
+	field  Field
+	op     operator
+	values []any
+}
+
+// wildcard сообщает, что значение строкового поля содержит шаблон *.
+func (n comparison) wildcard() bool {
+	if n.field.Kind != String || (n.op != opEq && n.op != opNe) {
+		return false
+	}
+	s, ok := n.values[0].(string)
+	return ok && strings.Contains(s, "*")
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", "%")
+
+func (n comparison) sql(b *strings.Builder, args *[]any) {
+	switch {
+	case n.op == opIn || n.op == opOut:
+		b.WriteString(n.field.Column)
+		if n.op == opOut {
+			b.WriteString(" NOT")
+		}
+		b.WriteString(" IN (")
+		for i, v := range n.values {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("?")
+			*args = append(*args, v)
+		}
+		b.WriteString(")")
+	case n.wildcard():
+		condition := n.field.Column + ` LIKE ? ESCAPE '\'`
+		if n.op == opNe {
+			condition = "NOT (" + condition + ")"
+		}
+		b.WriteString(condition)
+		*args = append(*args, likeEscaper.Replace(n.values[0].(string)))
+	default:
+		b.WriteString(n.field.Column + " " + sqlOperators[n.op] + " ?")
+		*args = append(*args, n.values[0])
+	}
+}
+
+func (n comparison) match(get func(string) any) bool {
+	actual := get(n.name)
+	switch n.op {
+	case opIn, opOut:
+		found := false
+		for _, v := range n.values {
+			if actual == v {
+				found = true
+				break
+			}
+		}
+		return found == (n.op == opIn)
+	case opEq, opNe:
+		equal := actual == n.values[0]
+		if n.wildcard() {
+			s, _ := actual.(string)
+			equal = glob(n.values[0].(string), s)
+		}
+		return equal == (n.op == opEq)
+	}
+
+	a, _ := actual.(int)
+	v := n.values[0].(int)
+	switch n.op {
+	case opLt:
+		return a < v
+	case opLe:
+		return a <= v
+	case opGt:
+		return a > v
+	}
+	return a >= v
+}
+
+// glob сопоставляет s с шаблоном, в котором * означает любую подстроку.
+func glob(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
+
+type tokenKind int
+
+const (
+	tEOF tokenKind = iota
+	tWord
+	tString
+	tOp
+	tLParen
+	tRParen
+	tComma
+	tSemi
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // смещение в байтах
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tEOF:
+		return "end of expression"
+	case tString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// isWordRune сообщает, может ли символ входить в слово без кавычек.
+func isWordRune(r rune) bool {
+	return !strings.ContainsRune(" \t\r\n\"'(),;=!<>", r)
+}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		start := i
+		switch {
+		case r == ' ' || r == '\t' || r == '\r' || r == '\n':
+			i += size
+			continue
+		case r == '(':
+			tokens = append(tokens, token{tLParen, "(", start})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tRParen, ")", start})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tComma, ",", start})
+			i++
+		case r == ';':
+			tokens = append(tokens, token{tSemi, ";", start})
+			i++
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(s) {
+					return nil, &Error{Pos: runePos(s, start), Msg: "unterminated string"}
+				}
+				c := s[i]
+				if c == byte(r) {
+					i++
+					break
+				}
+				if c == '\\' && i+1 < len(s) {
+					i++
+					c = s[i]
+				}
+				b.WriteByte(c)
+				i++
+			}
+			tokens = append(tokens, token{tString, b.String(), start})
+		case r == '=':
+			i++
+			if i < len(s) && s[i] == '=' {
+				i++
+			} else if j := strings.IndexByte(s[i:], '='); j > 0 && isLetters(s[i:i+j]) {
+				i += j + 1
+			}
+			op := s[start:i]
+			if _, ok := operators[op]; !ok {
+				return nil, &Error{Pos: runePos(s, start), Msg: fmt.Sprintf("unknown operator %q", op)}
+			}
+			tokens = append(tokens, token{tOp, op, start})
+		case r == '!' || r == '<' || r == '>':
+			i++
+			if i < len(s) && s[i] == '=' {
+				i++
+			}
+			op := s[start:i]
+			if op == "!" {
+				return nil, &Error{Pos: runePos(s, start), Msg: `unexpected "!", did you mean "!="`}
+			}
+			tokens = append(tokens, token{tOp, op, start})
+		default:
+			for i < len(s) {
+				r, size := utf8.DecodeRuneInString(s[i:])
+				if !isWordRune(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{tWord, s[start:i], start})
+		}
+	}
+	return append(tokens, token{tEOF, "", len(s)}), nil
+}
+
+func isLetters(s string) bool {
+	for _, c := range s {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func runePos(s string, offset int) int {
+	return utf8.RuneCountInString(s[:offset]) + 1
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	i      int
+	fields Fields
+	terms  int
+	depth  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) keyword(t token, word string) bool {
+	return t.kind == tWord && strings.EqualFold(t.text, word)
+}
+
+func (p *parser) errorf(t token, format string, args ...any) *Error {
+	return &Error{Pos: runePos(p.src, t.pos), Msg: fmt.Sprintf(format, args...)}
+}
+
+// parseOr: and {("or" | ",") and}
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); p.keyword(t, "or") || t.kind == tComma; t = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd: unary {("and" | ";") unary}
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); p.keyword(t, "and") || t.kind == tSemi; t = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary: "not" unary | "(" or ")" | comparison
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	switch {
+	case p.keyword(t, "not"), t.kind == tLParen:
+		p.next()
+		if p.depth++; p.depth > MaxDepth {
+			return nil, p.errorf(t, "expression is nested too deeply: more than %d levels", MaxDepth)
+		}
+		defer func() { p.depth-- }()
+
+		if t.kind == tWord {
+			x, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return negation{x: x}, nil
+		}
+
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tRParen {
+			return nil, p.errorf(closing, "expected \")\", got %s", closing)
+		}
+		return x, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison: field op value | field ("in" | "not" "in" | "=in=" | "=out=") "(" value {"," value} ")"
+func (p *parser) parseComparison() (node, error) {
+	t := p.next()
+	if t.kind != tWord {
+		return nil, p.errorf(t, "expected field name, got %s", t)
+	}
+	field, ok := p.fields[t.text]
+	if !ok {
+		return nil, p.errorf(t, "unknown field %q, allowed: %s", t.text, p.fields.names())
+	}
+	if p.terms++; p.terms > MaxTerms {
+		return nil, p.errorf(t, "expression is too complex: more than %d comparisons", MaxTerms)
+	}
+	n := comparison{name: t.text, field: field}
+
+	opToken := p.next()
+	switch {
+	case opToken.kind == tOp:
+		n.op = operators[opToken.text]
+	case p.keyword(opToken, "in"):
+		n.op = opIn
+	case p.keyword(opToken, "not") && p.keyword(p.peek(), "in"):
+		p.next()
+		n.op = opOut
+	default:
+		return nil, p.errorf(opToken, "expected operator after %q, got %s", t.text, opToken)
+	}
+	if field.Kind != Int && n.op >= opLt && n.op <= opGe {
+		return nil, p.errorf(opToken, "operator %q is not supported for field %q", opToken.text, t.text)
+	}
+
+	var raw []token
+	if n.op == opIn || n.op == opOut {
+		if open := p.next(); open.kind != tLParen {
+			return nil, p.errorf(open, "expected \"(\" after %q, got %s", opToken.text, open)
+		}
+		for {
+			v := p.next()
+			if v.kind != tWord && v.kind != tString {
+				return nil, p.errorf(v, "expected value, got %s", v)
+			}
+			if raw = append(raw, v); len(raw) > MaxValues {
+				return nil, p.errorf(v, "too many values: more than %d", MaxValues)
+			}
+			sep := p.next()
+			if sep.kind == tRParen {
+				break
+			}
+			if sep.kind != tComma {
+				return nil, p.errorf(sep, "expected \",\" or \")\", got %s", sep)
+			}
+		}
+	} else {
+		v := p.next()
+		if v.kind != tWord && v.kind != tString {
+			return nil, p.errorf(v, "expected value for %q, got %s", t.text, v)
+		}
+		raw = append(raw, v)
+	}
+
+	for _, v := range raw {
+		value, err := parseValue(field, v.text)
+		if err != nil {
+			return nil, p.errorf(v, "invalid value %s for field %q: %v", v, t.text, err)
+		}
+		n.values = append(n.values, value)
+	}
+	return n, nil
+}
+
+func parseValue(field Field, s string) (any, error) {
+	if field.Value != nil {
+		return field.Value(s)
+	}
+	if field.Kind == Int {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return v, nil
+	}
+	return s, nil
+}
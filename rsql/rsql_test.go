@@ -0,0 +1,142 @@
+package rsql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var genders = map[string]int{"male": 1, "female": 2}
+
+var testFields = Fields{
+	"id":   {Column: "id", Kind: Int},
+	"name": {Column: "name", Kind: String},
+	"age":  {Column: "age", Kind: Int},
+	"gender": {Column: "gender", Kind: Enum, Value: func(s string) (any, error) {
+		if g, ok := genders[s]; ok {
+			return g, nil
+		}
+		return nil, fmt.Errorf("must be male or female")
+	}},
+	"nationality": {Column: "nationality", Kind: Enum, Value: func(s string) (any, error) {
+		return strings.ToUpper(s), nil
+	}},
+}
+
+func TestSQL(t *testing.T) {
+	tests := []struct {
+		expr string
+		sql  string
+		args []any
+	}{
+		{"age>=30", "age >= ?", []any{30}},
+		{"age=ge=30", "age >= ?", []any{30}},
+		{"age=lt=18", "age < ?", []any{18}},
+		{"name==Иван", "name = ?", []any{"Иван"}},
+		{`name=="О'Брайен"`, "name = ?", []any{"О'Брайен"}},
+		{"name=ne='Иван Петров'", "name <> ?", []any{"Иван Петров"}},
+		{"name==Ив*", `name LIKE ? ESCAPE '\'`, []any{"Ив%"}},
+		{"name!=*ов_%", `NOT (name LIKE ? ESCAPE '\')`, []any{`%ов\_\%`}},
+		{"gender=female", "gender = ?", []any{2}},
+		{"id=in=(1,2,3)", "id IN (?, ?, ?)", []any{1, 2, 3}},
+		{"nationality=out=(ru,KZ)", "nationality NOT IN (?, ?)", []any{"RU", "KZ"}},
+		{"nationality not in (RU)", "nationality NOT IN (?)", []any{"RU"}},
+		{"age<18;name==a,name==b", "((age < ? AND name = ?) OR name = ?)", []any{18, "a", "b"}},
+		{"not (age>10 or age<5)", "NOT ((age > ? OR age < ?))", []any{10, 5}},
+		{"age>=30 and (nationality in ('RU','KZ') or gender=female)",
+			"(age >= ? AND (nationality IN (?, ?) OR gender = ?))", []any{30, "RU", "KZ", 2}},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.expr, testFields)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		sql, args := e.SQL()
+		if sql != tt.sql || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("Parse(%q).SQL() = %q %#v, want %q %#v", tt.expr, sql, args, tt.sql, tt.args)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{"", 1, "empty expression"},
+		{"age", 4, `expected operator after "age"`},
+		{"age>", 5, `expected value for "age"`},
+		{"age>=abc", 6, `invalid value "abc" for field "age"`},
+		{"bogus==1", 1, `unknown field "bogus", allowed: age, gender, id, name, nationality`},
+		{"name<a", 5, `operator "<" is not supported for field "name"`},
+		{"gender>male", 7, `operator ">" is not supported for field "gender"`},
+		{"gender==alien", 9, "must be male or female"},
+		{"(age>1", 7, `expected ")"`},
+		{"age>1)", 6, `unexpected ")"`},
+		{"age>1 and", 10, "expected field name"},
+		{"age>1 xor age<2", 7, `unexpected "xor"`},
+		{"nationality=in=()", 17, "expected value"},
+		{"nationality=in=(RU", 19, `expected "," or ")"`},
+		{"name=='unterminated", 7, "unterminated string"},
+		{"имя==1 or возраст>", 1, `unknown field "имя"`},
+		{strings.Repeat("(", MaxDepth+1) + "age>1" + strings.Repeat(")", MaxDepth+1), MaxDepth + 1, "nested too deeply"},
+		{strings.Repeat("age>1 and ", MaxTerms) + "age>1", MaxTerms*10 + 1, "too complex"},
+		{"id=in=(" + strings.Repeat("1,", MaxValues) + "1)", 8 + MaxValues*2, "too many values"},
+		{strings.Repeat("a", MaxLength+1), 1, "too long"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.expr, testFields)
+		var perr *Error
+		if !errors.As(err, &perr) {
+			t.Errorf("Parse(%.40q) = %v, want *Error", tt.expr, err)
+			continue
+		}
+		if perr.Pos != tt.pos || !strings.Contains(perr.Msg, tt.msg) {
+			t.Errorf("Parse(%.40q) = %v, want position %d: ...%s...", tt.expr, err, tt.pos, tt.msg)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	record := map[string]any{"id": 7, "name": "Иванов", "age": 30, "gender": 1, "nationality": "RU"}
+	get := func(field string) any { return record[field] }
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"age>=30", true},
+		{"age>30", false},
+		{"age<=30;age=gt=29", true},
+		{"name==Иванов", true},
+		{"name==Иван", false},
+		{"name==Иван*", true},
+		{"name==*нав*", false},
+		{"name==*ван*", true},
+		{"name==И*в*в", true},
+		{"name!=*ов", false},
+		{"gender==male", true},
+		{"gender!=male", false},
+		{"nationality in (kz, ru)", true},
+		{"nationality=out=(RU)", false},
+		{"id=in=(1,7)", true},
+		{"age<18,nationality==RU", true},
+		{"age<18 and nationality==RU", false},
+		{"not age<18", true},
+		{"not (gender==male or age>50)", false},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.expr, testFields)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := e.Match(get); got != tt.want {
+			t.Errorf("Parse(%q).Match = %t, want %t", tt.expr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Package validation проверяет входные данные по правилам из тега `validate`
+// и описывает ошибки в формате RFC 7807 (application/problem+json).
+//
+// Правила перечисляются через запятую:
+//
+//	required  - строка не пустая (для указателей - только если значение передано)
+//	max=N     - длина строки в символах или число не больше N
+//	min=N     - длина строки в символах или число не меньше N
+//	name      - буквы, пробелы, дефисы и апострофы, первая буква обязательна
+//	country   - код страны ISO 3166-1 alpha-2 заглавными буквами
+//	readonly  - поле вычисляется сервером и не может передаваться клиентом
+//	computed  - поле заполняется обогащением; передавать можно только при импорте
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FieldError - ошибка одного поля. Name - имя поля в JSON.
+type FieldError struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Errors - список ошибок полей.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Name+": "+fe.Reason)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Prefix добавляет префикс к именам полей, например "[2]" для элемента списка.
+func (e Errors) Prefix(prefix string) Errors {
+	out := make(Errors, len(e))
+	for i, fe := range e {
+		out[i] = FieldError{Name: prefix + "." + fe.Name, Reason: fe.Reason}
+	}
+	return out
+}
+
+// Problem - тело ответа об ошибке по RFC 7807.
+type Problem struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Status        int    `json:"status"`
+	Detail        string `json:"detail,omitempty"`
+	Instance      string `json:"instance,omitempty"`
+	InvalidParams Errors `json:"invalid-params,omitempty"`
+}
+
+// ContentType - тип содержимого ответа с Problem.
+const ContentType = "application/problem+json"
+
+// Problem описывает ошибки полей запроса к instance.
+func (e Errors) Problem(instance string) Problem {
+	return Problem{
+		Type:          "/problems/validation-error",
+		Title:         "Validation failed",
+		Status:        400,
+		Detail:        fmt.Sprintf("%d invalid field(s)", len(e)),
+		Instance:      instance,
+		InvalidParams: e,
+	}
+}
+
+// Validate проверяет структуру по тегам `validate`. Поля с правилом computed
+// запрещены. Возвращает Errors или nil.
+func Validate(v any) error {
+	return validate(v, false)
+}
+
+// ValidateImport проверяет структуру так же, как Validate, но разрешает поля computed.
+func ValidateImport(v any) error {
+	return validate(v, true)
+}
+
+func validate(v any, allowComputed bool) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+
+	var errs Errors
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+
+		value := rv.Field(i)
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+
+		for _, rule := range strings.Split(tag, ",") {
+			name, param, _ := strings.Cut(rule, "=")
+			if name == "computed" && allowComputed {
+				continue
+			}
+
+			check, ok := rules[name]
+			if !ok {
+				panic("validation: неизвестное правило " + name + " у поля " + field.Name)
+			}
+			if reason := check(value, param); reason != "" {
+				errs = append(errs, FieldError{Name: jsonName(field), Reason: reason})
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+type rule func(v reflect.Value, param string) string
+
+var rules = map[string]rule{
+	"required": func(v reflect.Value, _ string) string {
+		if v.Kind() == reflect.String && strings.TrimSpace(v.String()) == "" {
+			return "must not be empty"
+		}
+		return ""
+	},
+	"max": func(v reflect.Value, param string) string {
+		limit, _ := strconv.Atoi(param)
+		if size(v) > limit {
+			return "must be at most " + param + unit(v)
+		}
+		return ""
+	},
+	"min": func(v reflect.Value, param string) string {
+		limit, _ := strconv.Atoi(param)
+		if size(v) < limit {
+			return "must be at least " + param + unit(v)
+		}
+		return ""
+	},
+	"name": func(v reflect.Value, _ string) string {
+		s := strings.TrimSpace(v.String())
+		for i, r := range s {
+			if unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) {
+				continue
+			}
+			if i > 0 && (r == ' ' || r == '-' || r == '\'' || r == '’') {
+				continue
+			}
+			return "must contain only letters, spaces, hyphens and apostrophes"
+		}
+		return ""
+	},
+	"country": func(v reflect.Value, _ string) string {
+		s := v.String()
+		if s == "" {
+			return ""
+		}
+		if len(s) != 2 || s[0] < 'A' || s[0] > 'Z' || s[1] < 'A' || s[1] > 'Z' {
+			return "must be an ISO 3166-1 alpha-2 code"
+		}
+		return ""
+	},
+	"readonly": func(v reflect.Value, _ string) string {
+		if !empty(v) {
+			return "is read-only"
+		}
+		return ""
+	},
+	"computed": func(v reflect.Value, _ string) string {
+		if !empty(v) {
+			return "is computed by enrichment and must not be set"
+		}
+		return ""
+	},
+}
+
+// empty сообщает, что значение не задано: нулевое значение, пустой срез или map
+// (например, "enrichment": [] в теле запроса).
+func empty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+func size(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int())
+	}
+	return 0
+}
+
+func unit(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return " characters"
+	}
+	return ""
+}
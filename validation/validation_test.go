@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type input struct {
+	Id         int     `json:"id" validate:"readonly"`
+	Name       string  `json:"name" validate:"required,max=10,name"`
+	Nickname   *string `json:"nickname,omitempty" validate:"required,min=2"`
+	Age        int     `json:"age,omitempty" validate:"computed,min=0,max=150"`
+	Country    string  `json:"country,omitempty" validate:"computed,country"`
+	Details    []int   `json:"details,omitempty" validate:"readonly"`
+	Untagged   string
+	NoJSONName string `validate:"max=1"`
+}
+
+func TestValidate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name  string
+		input input
+		want  Errors
+	}{
+		{"valid", input{Name: "Анна-Мария"}, nil},
+		{"typographic apostrophe", input{Name: "О’Коннор"}, nil},
+		{"empty name", input{Name: "  "}, Errors{{"name", "must not be empty"}}},
+		{"too long", input{Name: "Абвгдеёжзий"}, Errors{{"name", "must be at most 10 characters"}}},
+		{"leading hyphen", input{Name: "-Иван"}, Errors{{"name", "must contain only letters, spaces, hyphens and apostrophes"}}},
+		{"digits", input{Name: "Иван2"}, Errors{{"name", "must contain only letters, spaces, hyphens and apostrophes"}}},
+		{"nil pointer skipped", input{Name: "Иван", Nickname: nil}, nil},
+		{"empty pointer", input{Name: "Иван", Nickname: str("")}, Errors{{"nickname", "must not be empty"}}},
+		{"short pointer", input{Name: "Иван", Nickname: str("И")}, Errors{{"nickname", "must be at least 2 characters"}}},
+		{"readonly", input{Id: 1, Name: "Иван"}, Errors{{"id", "is read-only"}}},
+		{"readonly empty slice", input{Name: "Иван", Details: []int{}}, nil},
+		{"readonly slice", input{Name: "Иван", Details: []int{1}}, Errors{{"details", "is read-only"}}},
+		{"computed", input{Name: "Иван", Age: 30, Country: "RU"}, Errors{
+			{"age", "is computed by enrichment and must not be set"},
+			{"country", "is computed by enrichment and must not be set"},
+		}},
+		{"field name without json tag", input{Name: "Иван", NoJSONName: "ab"}, Errors{{"NoJSONName", "must be at most 1 characters"}}},
+		{"first error per field", input{Name: strings.Repeat("1", 11)}, Errors{{"name", "must be at most 10 characters"}}},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.input)
+		var got Errors
+		if err != nil && !errors.As(err, &got) {
+			t.Fatalf("%s: error %T is not Errors", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Validate = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateImport(t *testing.T) {
+	tests := []struct {
+		name  string
+		input input
+		want  Errors
+	}{
+		{"computed allowed", input{Name: "Иван", Age: 30, Country: "RU"}, nil},
+		{"range still checked", input{Name: "Иван", Age: 151}, Errors{{"age", "must be at most 150"}}},
+		{"country code", input{Name: "Иван", Country: "ru"}, Errors{{"country", "must be an ISO 3166-1 alpha-2 code"}}},
+		{"readonly still rejected", input{Id: 1, Name: "Иван"}, Errors{{"id", "is read-only"}}},
+	}
+	for _, tt := range tests {
+		err := ValidateImport(&tt.input)
+		var got Errors
+		if err != nil && !errors.As(err, &got) {
+			t.Fatalf("%s: error %T is not Errors", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ValidateImport = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProblem(t *testing.T) {
+	errs := Errors{{"name", "must not be empty"}, {"age", "must be at most 150"}}.Prefix("[1]")
+	p := errs.Problem("/api/v1/people/bulk")
+	if p.Status != 400 || p.Detail != "2 invalid field(s)" || p.Instance != "/api/v1/people/bulk" {
+		t.Errorf("Problem = %+v", p)
+	}
+	if p.InvalidParams[0].Name != "[1].name" || p.InvalidParams[1].Name != "[1].age" {
+		t.Errorf("prefixed names: %v", p.InvalidParams)
+	}
+	if got, want := errs.Error(), "[1].name: must not be empty; [1].age: must be at most 150"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
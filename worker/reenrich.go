@@ -0,0 +1,172 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"task/config"
+	"task/internal"
+	"task/models"
+	"task/repository"
+	"time"
+)
+
+const (
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// reenrichPageSize - число записей, загружаемых и обогащаемых за одну итерацию задачи.
+const reenrichPageSize = 100
+
+// ReenrichJob - фоновая задача повторного обогащения записей по фильтру.
+type ReenrichJob struct {
+	Id         int        `json:"id"`
+	Status     string     `json:"status"`
+	Processed  int        `json:"processed"`
+	Failed     int        `json:"failed"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// FetchPage загружает до limit записей, попадающих под фильтр задачи, с ID больше
+// afterID в порядке возрастания ID.
+type FetchPage func(afterID, limit int) ([]models.Person, error)
+
+var (
+	reenrichMu     sync.Mutex
+	reenrichJobs   = map[int]*ReenrichJob{}
+	reenrichLastId int
+)
+
+// StartReenrich запускает фоновую задачу повторного обогащения всех записей,
+// которые возвращает fetch. Поля, заданные вручную или импортированные, сохраняются.
+func StartReenrich(fetch FetchPage, countryID string) ReenrichJob {
+	reenrichMu.Lock()
+	pruneJobs()
+	reenrichLastId++
+	job := &ReenrichJob{Id: reenrichLastId, Status: JobRunning, StartedAt: time.Now()}
+	reenrichJobs[job.Id] = job
+	snapshot := *job
+	reenrichMu.Unlock()
+
+	go runReenrich(job, fetch, countryID)
+	return snapshot
+}
+
+// GetReenrich возвращает состояние задачи повторного обогащения.
+func GetReenrich(id int) (ReenrichJob, bool) {
+	reenrichMu.Lock()
+	defer reenrichMu.Unlock()
+
+	pruneJobs()
+	job, ok := reenrichJobs[id]
+	if !ok {
+		return ReenrichJob{}, false
+	}
+	return *job, true
+}
+
+// pruneJobs удаляет задачи, завершившиеся раньше config.ReenrichJobTTL. Вызывается под reenrichMu.
+func pruneJobs() {
+	for id, job := range reenrichJobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > config.ReenrichJobTTL {
+			delete(reenrichJobs, id)
+		}
+	}
+}
+
+func updateJob(job *ReenrichJob, update func(*ReenrichJob)) {
+	reenrichMu.Lock()
+	defer reenrichMu.Unlock()
+	update(job)
+}
+
+func runReenrich(job *ReenrichJob, fetch FetchPage, countryID string) {
+	// Обход идёт по возрастанию ID, а не по смещению: записи, переставшие после
+	// обогащения попадать под фильтр, не сдвигают следующие страницы.
+	ctx := internal.WithRefresh(context.Background())
+	for lastId := 0; ; {
+		page, err := fetch(lastId, reenrichPageSize)
+		if err != nil {
+			finishJob(job, err)
+			return
+		}
+		if len(page) == 0 {
+			break
+		}
+		lastId = page[len(page)-1].Id
+
+		queries := make([]internal.Query, len(page))
+		for i, p := range page {
+			queries[i] = internal.QueryFor(p, countryID)
+		}
+
+		enriched, err := internal.EnrichBatch(ctx, queries)
+		if err != nil {
+			finishJob(job, err)
+			return
+		}
+
+		failed := 0
+		for i, res := range enriched {
+			_, saved, err := SaveReenriched(page[i].Id, queries[i], countryID, res)
+			if err != nil {
+				config.Logger.Error("Ошибка сохранения обогащения: ", err)
+				failed++
+			} else if !saved {
+				config.Logger.Infof("Запись с ID %d удалена или изменена во время обогащения, повторное обогащение пропущено", page[i].Id)
+			}
+		}
+
+		updateJob(job, func(j *ReenrichJob) {
+			j.Processed += len(page)
+			j.Failed += failed
+		})
+		if len(page) < reenrichPageSize {
+			break
+		}
+	}
+
+	finishJob(job, nil)
+}
+
+// SaveReenriched атомарно перечитывает запись и применяет к ней результат повторного
+// обогащения res, полученный по запросу q, чтобы не затереть изменения, сделанные во
+// время обогащения. Удалённые и переименованные за это время записи не сохраняются:
+// тогда возвращается false.
+func SaveReenriched(id int, q internal.Query, countryID string, res internal.Result) (models.Person, bool, error) {
+	var saved models.Person
+	ok, err := repository.UpdateEnrichment(id, func(person *models.Person) bool {
+		if internal.QueryFor(*person, countryID) != q {
+			return false
+		}
+		res.Reapply(person)
+		person.EnrichmentStatus = models.EnrichmentDone
+		person.EnrichmentError = ""
+		saved = *person
+		return true
+	})
+	return saved, ok, err
+}
+
+func finishJob(job *ReenrichJob, err error) {
+	var processed int
+	updateJob(job, func(j *ReenrichJob) {
+		now := time.Now()
+		j.FinishedAt = &now
+		j.Status = JobDone
+		if err != nil {
+			j.Status = JobFailed
+			j.Error = err.Error()
+		}
+		processed = j.Processed
+	})
+
+	if err != nil {
+		config.Logger.Errorf("Задача повторного обогащения %d завершилась с ошибкой: %v", job.Id, err)
+		return
+	}
+	config.Logger.Infof("Задача повторного обогащения %d завершена: обработано %d записей", job.Id, processed)
+}
@@ -0,0 +1,184 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"task/config"
+	"task/internal"
+	"task/models"
+	"task/repository"
+	"time"
+)
+
+// job хранит только ID: запись перечитывается перед обогащением, чтобы не затереть
+// изменения, сделанные, пока задача ждала в очереди.
+type job struct {
+	id        int
+	countryID string
+	attempt   int
+}
+
+var jobs chan job
+
+// queued - ID записей, задачи которых ждут в очереди, выполняются или ждут повтора.
+// Не даёт периодической проверке поставить запись в очередь дважды.
+var queued sync.Map
+
+// ErrQueueFull возвращается Enqueue, если очередь обогащения заполнена.
+var ErrQueueFull = errors.New("очередь обогащения заполнена")
+
+func envInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return fallback
+}
+
+// Start запускает пул воркеров асинхронного обогащения (ENRICH_WORKERS) и
+// ставит в очередь записи, оставшиеся в статусе pending после перезапуска. Затем
+// такие записи ищутся каждые ENRICH_SWEEP_INTERVAL: так обрабатываются записи,
+// для которых очередь была заполнена.
+func Start() {
+	jobs = make(chan job, envInt("ENRICH_QUEUE_SIZE", config.EnrichQueueSize))
+
+	workers := envInt("ENRICH_WORKERS", config.EnrichWorkers)
+	for i := 0; i < workers; i++ {
+		go run()
+	}
+
+	go func() {
+		sweep()
+		ticker := time.NewTicker(envDuration("ENRICH_SWEEP_INTERVAL", config.EnrichSweepInterval))
+		defer ticker.Stop()
+		for range ticker.C {
+			sweep()
+		}
+	}()
+}
+
+// sweep ставит в очередь записи в статусе pending, которых в ней ещё нет.
+// В отличие от Enqueue, ждёт освобождения места в очереди.
+func sweep() {
+	pending, err := repository.GetPendingEnrichment()
+	if err != nil {
+		config.Logger.Error("Ошибка загрузки ожидающих обогащения записей: ", err)
+		return
+	}
+	added := 0
+	for _, p := range pending {
+		if _, ok := queued.LoadOrStore(p.Id, struct{}{}); ok {
+			continue
+		}
+		jobs <- job{id: p.Id}
+		added++
+	}
+	if added > 0 {
+		config.Logger.Infof("В очередь обогащения поставлено %d записей", added)
+	}
+}
+
+// Enqueue ставит запись в очередь на обогащение, не блокируя вызывающего. countryID -
+// необязательная подсказка страны для провайдеров; после перезапуска она не сохраняется.
+// Если очередь заполнена, возвращается ErrQueueFull: запись остаётся в статусе pending
+// и будет поставлена в очередь периодической проверкой (без подсказки страны).
+func Enqueue(person models.Person, countryID string) error {
+	if _, ok := queued.LoadOrStore(person.Id, struct{}{}); ok {
+		return nil
+	}
+	select {
+	case jobs <- job{id: person.Id, countryID: countryID}:
+		return nil
+	default:
+		queued.Delete(person.Id)
+		return ErrQueueFull
+	}
+}
+
+func run() {
+	for j := range jobs {
+		if !process(j) {
+			queued.Delete(j.id)
+		}
+	}
+}
+
+// process обогащает запись и возвращает true, если задача отложена для повтора.
+func process(j job) bool {
+	person, err := repository.GetPerson(j.id)
+	if errors.Is(err, repository.ErrNotFound) {
+		config.Logger.Infof("Запись с ID %d удалена, обогащение пропущено", j.id)
+		return false
+	}
+	if err != nil {
+		return retry(j, err)
+	}
+	if person.EnrichmentStatus != models.EnrichmentPending {
+		config.Logger.Debugf("Запись с ID %d уже не ожидает обогащения (%s), задача пропущена", j.id, person.EnrichmentStatus)
+		return false
+	}
+
+	res, err := internal.EnrichPerson(context.Background(), internal.QueryFor(person, j.countryID))
+	var quotaErr *internal.QuotaError
+	if errors.As(err, &quotaErr) {
+		delay := time.Until(quotaErr.ResetAt)
+		config.Logger.Warnf("Квота %s исчерпана, обогащение записи с ID %d отложено на %s", quotaErr.Provider, j.id, delay)
+		time.AfterFunc(delay, func() { jobs <- j })
+		return true
+	}
+	if err != nil {
+		return retry(j, err)
+	}
+
+	// Запись перечитывается в транзакции сохранения: ручные правки, сделанные
+	// во время запроса к провайдерам, не затираются.
+	saved, err := repository.UpdateEnrichment(j.id, func(person *models.Person) bool {
+		if person.EnrichmentStatus != models.EnrichmentPending {
+			return false
+		}
+		res.Reapply(person)
+		return true
+	})
+	if err != nil {
+		config.Logger.Error("Ошибка сохранения обогащения: ", err)
+		return false
+	}
+	if !saved {
+		config.Logger.Infof("Запись с ID %d удалена или обогащена во время обработки, результат не сохранён", j.id)
+		return false
+	}
+	config.Logger.Infof("Успешно обогащена запись с ID %d", j.id)
+	return false
+}
+
+// retry повторяет задачу с экспоненциальной задержкой или, если попытки исчерпаны,
+// помечает запись как failed. Возвращает true, если повтор запланирован.
+func retry(j job, err error) bool {
+	j.attempt++
+	maxAttempts := envInt("ENRICH_MAX_ATTEMPTS", config.EnrichMaxAttempts)
+	if j.attempt >= maxAttempts {
+		config.Logger.Errorf("Обогащение записи с ID %d не удалось после %d попыток: %v", j.id, j.attempt, err)
+		if err := repository.SetEnrichmentStatus(j.id, models.EnrichmentFailed, err.Error()); err != nil {
+			config.Logger.Error("Ошибка обновления статуса обогащения: ", err)
+		}
+		return false
+	}
+
+	delay := config.EnrichRetryDelay << (j.attempt - 1)
+	config.Logger.Warnf("Ошибка обогащения записи с ID %d (попытка %d), повтор через %s: %v", j.id, j.attempt, delay, err)
+	time.AfterFunc(delay, func() { jobs <- j })
+	return true
+}
@@ -0,0 +1,200 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"task/config"
+	"task/internal"
+	"task/models"
+	"task/repository"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	config.LoadLoger()
+	male := 1.0
+	internal.Register(internal.NewOffline([]internal.NameStats{{
+		Name:      "Иван",
+		Ages:      map[int]int{40: 1},
+		Male:      &male,
+		Countries: map[string]float64{"RU": 0.9},
+	}}))
+	os.Exit(m.Run())
+}
+
+func pendingPerson(t *testing.T) models.Person {
+	t.Helper()
+	p := models.Person{Name: "Иван", Surname: "Петров", EnrichmentStatus: models.EnrichmentPending}
+	if err := repository.CreatePerson(&p); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestProcessKeepsChangesMadeWhileQueued(t *testing.T) {
+	repository.Use(repository.NewMemory())
+	p := pendingPerson(t)
+
+	// Пока задача ждёт в очереди, возраст задаётся вручную.
+	p.Age = 25
+	p.AgeProvenance = models.NewProvenance(models.SourceManual)
+	if err := repository.UpdatePerson(p); err != nil {
+		t.Fatal(err)
+	}
+
+	process(job{id: p.Id})
+
+	got, err := repository.GetPerson(p.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.EnrichmentStatus != models.EnrichmentDone {
+		t.Fatalf("status = %s, want done", got.EnrichmentStatus)
+	}
+	if got.Age != 25 || got.AgeProvenance.Source != models.SourceManual {
+		t.Errorf("age = %d (%s), want manual 25", got.Age, got.AgeProvenance.Source)
+	}
+	if got.Gender != models.Male || got.Nationality != "RU" {
+		t.Errorf("gender = %s, nationality = %s, want enriched male RU", got.Gender, got.Nationality)
+	}
+}
+
+// hookEnricher вызывает hook во время запроса, имитируя медленного провайдера,
+// пока запись меняют через API.
+type hookEnricher struct {
+	hook func()
+}
+
+func (hookEnricher) Name() string { return "hook" }
+
+func (e hookEnricher) Enrich(ctx context.Context, q internal.Query) (internal.Result, error) {
+	e.hook()
+	return internal.Result{}, nil
+}
+
+func TestProcessKeepsChangesMadeDuringEnrichment(t *testing.T) {
+	repository.Use(repository.NewMemory())
+	p := pendingPerson(t)
+
+	internal.Register(hookEnricher{hook: func() {
+		p.Age = 25
+		p.AgeProvenance = models.NewProvenance(models.SourceManual)
+		if err := repository.UpdatePerson(p); err != nil {
+			t.Error(err)
+		}
+	}})
+	defer internal.Unregister("hook")
+
+	process(job{id: p.Id})
+
+	got, err := repository.GetPerson(p.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.EnrichmentStatus != models.EnrichmentDone {
+		t.Fatalf("status = %s, want done", got.EnrichmentStatus)
+	}
+	if got.Age != 25 || got.AgeProvenance.Source != models.SourceManual {
+		t.Errorf("age = %d (%s), want manual 25", got.Age, got.AgeProvenance.Source)
+	}
+	if got.Gender != models.Male || got.Nationality != "RU" {
+		t.Errorf("gender = %s, nationality = %s, want enriched male RU", got.Gender, got.Nationality)
+	}
+}
+
+func TestProcessSkipsFinishedAndDeleted(t *testing.T) {
+	repository.Use(repository.NewMemory())
+
+	done := pendingPerson(t)
+	if err := repository.SetEnrichmentStatus(done.Id, models.EnrichmentDone, ""); err != nil {
+		t.Fatal(err)
+	}
+	process(job{id: done.Id})
+	if got, _ := repository.GetPerson(done.Id); got.Age != 0 || len(got.Enrichment) != 0 {
+		t.Errorf("finished record was enriched again: %+v", got)
+	}
+
+	deleted := pendingPerson(t)
+	if err := repository.DeletePerson(deleted.Id); err != nil {
+		t.Fatal(err)
+	}
+	process(job{id: deleted.Id})
+	if _, err := repository.GetPerson(deleted.Id); err != repository.ErrNotFound {
+		t.Errorf("deleted record reappeared: %v", err)
+	}
+}
+
+func TestSweepEnqueuesRecordsLeftPending(t *testing.T) {
+	repository.Use(repository.NewMemory())
+	jobs = make(chan job, 1)
+	defer func() { jobs = nil }()
+
+	first, second := pendingPerson(t), pendingPerson(t)
+	if err := Enqueue(first, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := Enqueue(second, ""); err != ErrQueueFull {
+		t.Fatalf("Enqueue into a full queue: %v", err)
+	}
+	if err := Enqueue(first, ""); err != nil || len(jobs) != 1 {
+		t.Fatalf("queued record was enqueued again: %v, %d jobs", err, len(jobs))
+	}
+
+	j := <-jobs
+	if !process(j) {
+		queued.Delete(j.id)
+	}
+
+	sweep()
+	if len(jobs) != 1 {
+		t.Fatalf("sweep queued %d jobs, want 1", len(jobs))
+	}
+	if j := <-jobs; j.id != second.Id {
+		t.Errorf("sweep queued record %d, want %d", j.id, second.Id)
+	}
+	queued.Delete(second.Id)
+}
+
+func TestReenrichCoversRecordsLeavingFilter(t *testing.T) {
+	repository.Use(repository.NewMemory())
+	const total = 2*reenrichPageSize + 50
+	for i := 0; i < total; i++ {
+		p := models.Person{Name: "Иван", Surname: "Петров"}
+		if err := repository.CreatePerson(&p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// После обогащения национальность становится RU, и запись выпадает из фильтра.
+	filter := repository.PeopleFilter{Nationalities: []string{""}}
+	fetch := func(afterID, limit int) ([]models.Person, error) {
+		filter.AfterId = afterID
+		return repository.GetPeople(filter, limit, 0)
+	}
+	job := &ReenrichJob{Id: 1, Status: JobRunning}
+	runReenrich(job, fetch, "")
+
+	if job.Status != JobDone || job.Processed != total || job.Failed != 0 {
+		t.Fatalf("job = %+v", job)
+	}
+	left, err := repository.GetPeople(repository.PeopleFilter{Nationalities: []string{""}}, -1, 0)
+	if err != nil || len(left) != 0 {
+		t.Errorf("%d records left unenriched: %v", len(left), err)
+	}
+}
+
+func TestFinishedReenrichJobsExpire(t *testing.T) {
+	finished := time.Now().Add(-config.ReenrichJobTTL - time.Minute)
+	reenrichMu.Lock()
+	reenrichJobs[-1] = &ReenrichJob{Id: -1, Status: JobDone, FinishedAt: &finished}
+	reenrichJobs[-2] = &ReenrichJob{Id: -2, Status: JobRunning, StartedAt: finished}
+	reenrichMu.Unlock()
+
+	if _, ok := GetReenrich(-1); ok {
+		t.Error("expired job is still returned")
+	}
+	if _, ok := GetReenrich(-2); !ok {
+		t.Error("running job was dropped")
+	}
+}